@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	Service *services.AccountService
+}
+
+func NewAccountHandler(service *services.AccountService) *AccountHandler {
+	return &AccountHandler{Service: service}
+}
+
+func toAccountResponse(account *models.Account) dto.AccountResponse {
+	return dto.AccountResponse{
+		ID:        account.ID,
+		Name:      account.Name,
+		Type:      account.Type,
+		Balance:   account.Balance,
+		CreatedAt: account.CreatedAt,
+	}
+}
+
+// @BasePath /api/v1
+// @Summary Cria uma conta
+// @Description Cria uma conta (corrente, poupança, dinheiro ou cartão de crédito) para o usuário
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param account body dto.AccountCreateParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 201 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts [post]
+func (h *AccountHandler) Create(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.AccountCreateInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	account, err := h.Service.CreateAccount(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, toAccountResponse(account))
+}
+
+// @BasePath /api/v1
+// @Summary Lista as contas
+// @Description Lista as contas do usuário e o saldo total
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.AccountListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts [get]
+func (h *AccountHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	accounts, err := h.Service.ListAccounts(userID, utils.GetWorkspaceID(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respAccounts := []dto.AccountResponse{}
+	for i := range accounts {
+		respAccounts = append(respAccounts, toAccountResponse(&accounts[i]))
+	}
+
+	resp := dto.AccountListResponse{
+		Data:         respAccounts,
+		TotalBalance: h.Service.TotalBalance(accounts),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Retorna uma conta
+// @Description Retorna uma conta do usuário em questão
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da conta"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id} [get]
+func (h *AccountHandler) Retrieve(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	account, err := h.Service.RetrieveAccount(userID, utils.GetWorkspaceID(c), id)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toAccountResponse(account))
+}
+
+// @BasePath /api/v1
+// @Summary Atualiza uma conta
+// @Description Atualiza nome e tipo de uma conta do usuário
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da conta"
+// @Param account body dto.AccountUpdateParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id} [put]
+func (h *AccountHandler) Update(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.AccountInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	account, err := h.Service.UpdateAccount(userID, utils.GetWorkspaceID(c), id, input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toAccountResponse(account))
+}
+
+// @BasePath /api/v1
+// @Summary Atualiza o saldo de uma conta
+// @Description Ajusta manualmente o saldo de uma conta do usuário
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da conta"
+// @Param data body dto.BalanceUpdateParam true "Novo saldo"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.AccountUpdateBalanceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/balance [patch]
+func (h *AccountHandler) UpdateBalance(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.AccountUpdateBalanceInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.UpdateBalance(userID, utils.GetWorkspaceID(c), id, input.Balance); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AccountUpdateBalanceResponse(input))
+}
+
+// @BasePath /api/v1
+// @Summary Deleta uma conta
+// @Description Deleta uma conta do usuário que não possua transações nem transferências
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da conta"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id} [delete]
+func (h *AccountHandler) Delete(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteAccount(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		if errors.Is(err, services.ErrAccountInUse) {
+			utils.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	Service *services.APIKeyService
+}
+
+func NewAPIKeyHandler(service *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{Service: service}
+}
+
+func toAPIKeyResponse(key *models.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scope:      key.Scope,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// @BasePath /api/v1
+// @Summary Cria uma chave de API
+// @Description Cria uma chave de API pessoal (escopo read ou write, expiração opcional). A chave é exibida uma única vez e deve ser enviada em "Authorization: Bearer fk_..." ou "X-API-Key".
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Param api_key body dto.APIKeyInput true "Request body"
+// @Success 201 {object} dto.APIKeyCreateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.APIKeyInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	key, plain, err := h.Service.CreateAPIKey(userID, input)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIKeyCreateResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            plain,
+	})
+}
+
+// @BasePath /api/v1
+// @Summary Lista as chaves de API
+// @Description Lista as chaves de API do usuário, com último uso, expiração e revogação
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	keys, err := h.Service.ListAPIKeys(userID)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := []dto.APIKeyResponse{}
+	for i := range keys {
+		resp = append(resp, toAPIKeyResponse(&keys[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Revoga uma chave de API
+// @Description Revoga uma chave de API do usuário. A chave deixa de ser aceita imediatamente.
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da chave"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.RevokeAPIKey(userID, id); err != nil {
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
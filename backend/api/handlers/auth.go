@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/daviolvr/Fintrack/internal/dto"
 	"github.com/daviolvr/Fintrack/internal/services"
@@ -20,7 +22,7 @@ func NewAuthHandler(service *services.AuthService) *AuthHandler {
 
 // @BasePath /api/v1
 // @Summary Registra um usuário
-// @Description Registra um usuário no sistema
+// @Description Registra um usuário no sistema, criando o workspace pessoal com as categorias modelo do idioma informado (padrão: pt-BR)
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -45,7 +47,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // @BasePath /api/v1
 // @Summary Login de usuários
-// @Description Login de usuários no sistema
+// @Description Login de usuários no sistema. Com 2FA ativo, retorna um token de desafio para /login/2fa.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -54,6 +56,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -62,14 +65,69 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, message, err := h.Service.LoginUser(c, input)
+	user, challenge, err := h.Service.LoginUser(c, input)
 	if err != nil {
+		if errors.Is(err, services.ErrTooManyLoginAttempts) {
+			utils.RespondError(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			utils.RespondError(c, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.RespondError(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
+	// Usuário com 2FA: o login continua em /login/2fa
+	if challenge != "" {
+		c.JSON(http.StatusOK, dto.LoginChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": message,
+		"message": "Login realizado com sucesso",
+		"user": gin.H{
+			"id":    user.ID,
+			"email": user.Email,
+			"name":  user.FirstName + " " + user.LastName,
+		},
+	})
+}
+
+// @BasePath /api/v1
+// @Summary Segunda etapa do login (2FA)
+// @Description Troca o token de desafio retornado por /login e um código TOTP (ou de recuperação) pelos tokens de acesso
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param login body dto.TwoFactorLoginInput true "Request body"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Router /login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var input dto.TwoFactorLoginInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	user, err := h.Service.LoginTwoFactor(c, input)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyLoginAttempts) {
+			utils.RespondError(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login realizado com sucesso",
 		"user": gin.H{
 			"id":    user.ID,
 			"email": user.Email,
@@ -95,13 +153,240 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, err := h.Service.RefreshToken(input.RefreshToken)
+	resp, err := h.Service.RefreshToken(c, input.RefreshToken)
 	if err != nil {
-		utils.RespondError(c, http.StatusUnauthorized, err.Error())
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+			utils.RespondError(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, utils.ErrInternalServer.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"access_token": newAccessToken,
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Logout
+// @Description Encerra a sessão do refresh token informado, revogando-o junto com os tokens rotacionados do mesmo login
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh_token body dto.RefreshTokenInput true "Refresh Token"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var input dto.RefreshTokenInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.Logout(c, input.RefreshToken); err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			utils.RespondError(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, utils.ErrInternalServer.Error())
+		return
+	}
+
+	utils.RespondMessage(c, "Logout realizado com sucesso")
+}
+
+// @BasePath /api/v1
+// @Summary Logout de todas as sessões
+// @Description Revoga todos os refresh tokens do usuário, encerrando as sessões em todos os dispositivos
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.MessageResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /logout/all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	if err := h.Service.LogoutAll(c, userID); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondMessage(c, "Todas as sessões foram encerradas")
+}
+
+// @BasePath /api/v1
+// @Summary Esqueci minha senha
+// @Description Envia por email um link de uso único para redefinir a senha. A resposta é a mesma para emails não cadastrados.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body dto.ForgotPasswordInput true "Request body"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var input dto.ForgotPasswordInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.ForgotPassword(input.Email); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.ErrInternalServer.Error())
+		return
+	}
+
+	utils.RespondMessage(c, "Se o email estiver cadastrado, você receberá um link para redefinir a senha")
+}
+
+// @BasePath /api/v1
+// @Summary Redefine a senha
+// @Description Redefine a senha usando o token recebido por email. Todas as sessões do usuário são encerradas.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body dto.ResetPasswordInput true "Request body"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var input dto.ResetPasswordInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.ResetPassword(input.Token, input.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidEmailToken) {
+			utils.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, utils.ErrInternalServer.Error())
+		return
+	}
+
+	utils.RespondMessage(c, "Senha redefinida com sucesso")
+}
+
+// @BasePath /api/v1
+// @Summary Verifica o email
+// @Description Confirma o email do usuário com o token recebido no cadastro
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body dto.VerifyEmailInput true "Request body"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /email/verify [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var input dto.VerifyEmailInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.VerifyEmail(input.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidEmailToken) {
+			utils.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, utils.ErrInternalServer.Error())
+		return
+	}
+
+	utils.RespondMessage(c, "Email verificado com sucesso")
+}
+
+// @BasePath /api/v1
+// @Summary Reenvia o email de verificação
+// @Description Envia um novo link de verificação. A resposta é a mesma para emails não cadastrados ou já verificados.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body dto.ForgotPasswordInput true "Request body"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var input dto.ForgotPasswordInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.ResendVerification(input.Email); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.ErrInternalServer.Error())
+		return
+	}
+
+	utils.RespondMessage(c, "Se o email estiver pendente de verificação, você receberá um novo link")
+}
+
+// @BasePath /api/v1
+// @Summary Histórico de login
+// @Description Lista as tentativas de login na conta do usuário (sucesso ou falha, motivo, IP e navegador)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param page query int false "Página"
+// @Param limit query int false "Itens por página (máx. 100)"
+// @Success 200 {object} dto.PaginatedLoginAttemptsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/login-history [get]
+func (h *AuthHandler) LoginHistory(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	// Conversões de query params
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil && val > 0 {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 100 {
+			limit = val
+		}
+	}
+
+	attempts, total, err := h.Service.LoginHistory(userID, page, limit)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respAttempts := []dto.LoginAttemptResponse{}
+	for _, attempt := range attempts {
+		respAttempts = append(respAttempts, dto.LoginAttemptResponse{
+			ID:        attempt.ID,
+			Success:   attempt.Success,
+			Reason:    attempt.Reason,
+			IP:        attempt.IP,
+			UserAgent: attempt.UserAgent,
+			CreatedAt: attempt.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedLoginAttemptsResponse{
+		Data:       respAttempts,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
 	})
 }
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetHandler struct {
+	Service *services.BudgetService
+}
+
+func NewBudgetHandler(service *services.BudgetService) *BudgetHandler {
+	return &BudgetHandler{Service: service}
+}
+
+func toBudgetResponse(budget *models.Budget) dto.BudgetResponse {
+	resp := dto.BudgetResponse{
+		ID:         budget.ID,
+		CategoryID: budget.CategoryID,
+		Limit:      budget.Limit,
+		StartMonth: budget.StartMonth.Format("2006-01"),
+		Rollover:   budget.Rollover,
+		CreatedAt:  budget.CreatedAt,
+	}
+	if budget.EndMonth != nil {
+		resp.EndMonth = budget.EndMonth.Format("2006-01")
+	}
+	return resp
+}
+
+// @BasePath /api/v1
+// @Summary Cria um orçamento
+// @Description Cria um orçamento mensal para uma categoria, com rollover opcional do valor não gasto
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Param budget body dto.BudgetParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 201 {object} dto.BudgetResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /budgets [post]
+func (h *BudgetHandler) Create(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.BudgetInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	budget, err := h.Service.CreateBudget(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, toBudgetResponse(budget))
+}
+
+// @BasePath /api/v1
+// @Summary Lista os orçamentos
+// @Description Lista os orçamentos do usuário
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {array} dto.BudgetResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /budgets [get]
+func (h *BudgetHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	budgets, err := h.Service.ListBudgets(userID, utils.GetWorkspaceID(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := []dto.BudgetResponse{}
+	for i := range budgets {
+		resp = append(resp, toBudgetResponse(&budgets[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Situação dos orçamentos no mês
+// @Description Retorna, por categoria, o valor gasto, o restante e o percentual usado do orçamento
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Param month query string false "Mês no formato YYYY-MM (padrão: mês atual)"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.BudgetStatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /budgets/status [get]
+func (h *BudgetHandler) Status(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	month := time.Now()
+	if m := c.Query("month"); m != "" {
+		parsed, err := time.Parse("2006-01", m)
+		if err != nil {
+			utils.RespondError(c, http.StatusBadRequest, "mês inválido, use o formato YYYY-MM")
+			return
+		}
+		month = parsed
+	}
+
+	resp, err := h.Service.Status(userID, utils.GetWorkspaceID(c), month)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Atualiza um orçamento
+// @Description Atualiza um orçamento do usuário
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do orçamento"
+// @Param budget body dto.BudgetParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.BudgetResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /budgets/{id} [put]
+func (h *BudgetHandler) Update(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.BudgetInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	budget, err := h.Service.UpdateBudget(userID, utils.GetWorkspaceID(c), id, input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toBudgetResponse(budget))
+}
+
+// @BasePath /api/v1
+// @Summary Deleta um orçamento
+// @Description Deleta um orçamento do usuário
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do orçamento"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /budgets/{id} [delete]
+func (h *BudgetHandler) Delete(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteBudget(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
 	"github.com/daviolvr/Fintrack/internal/services"
 	"github.com/daviolvr/Fintrack/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -18,15 +20,52 @@ func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
 	return &CategoryHandler{Service: service}
 }
 
+func toCategoryResponse(category *models.Category) dto.CategoryResponse {
+	return dto.CategoryResponse{
+		ID:       category.ID,
+		Name:     category.Name,
+		Type:     category.Type,
+		ParentID: category.ParentID,
+		Color:    category.Color,
+		Icon:     category.Icon,
+		Archived: category.ArchivedAt != nil,
+	}
+}
+
+// Responde os erros de validação de categorias. Retorna false se o erro não for desse tipo.
+func respondCategoryError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrCategoryParentNotFound),
+		errors.Is(err, services.ErrCategoryCycle),
+		errors.Is(err, services.ErrCategoryTargetNotFound),
+		errors.Is(err, services.ErrCategorySameTarget),
+		errors.Is(err, services.ErrCategoryMergeIntoChild),
+		errors.Is(err, services.ErrCategoryTemplateNotFound),
+		errors.Is(err, services.ErrCategoryArchived):
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrCategoryInUse),
+		errors.Is(err, services.ErrCategoryExists),
+		errors.Is(err, services.ErrCategoryTypeMismatch),
+		errors.Is(err, services.ErrCategoryBudgetConflict):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
 // @BasePath /api/v1
 // @Summary Cria uma categoria
 // @Description Cria uma categoria de transação
 // @Tags category
 // @Accept json
 // @Produce json
-// @Param name path string true "Nome da categoria"
+// @Param category body dto.CategoryInput true "Dados da categoria (parent_id opcional cria uma subcategoria)"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 201 {object} dto.CategoryResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 501 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /categories [post]
@@ -42,26 +81,29 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 		return
 	}
 
-	category, err := h.Service.CreateCategory(userID, input.Name)
+	category, err := h.Service.CreateCategory(userID, utils.GetWorkspaceID(c), input)
 	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if respondCategoryError(c, err) {
+			return
+		}
 		utils.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	resp := dto.CategoryResponse{
-		ID:   category.ID,
-		Name: category.Name,
-	}
-
-	c.JSON(http.StatusCreated, resp)
+	c.JSON(http.StatusCreated, toCategoryResponse(category))
 }
 
 // @BasePath /api/v1
 // @Summary Lista as categorias
-// @Description Lista as categorias do usuário
+// @Description Lista as categorias do usuário. Categorias arquivadas só aparecem com include_archived=true.
 // @Tags category
 // @Accept json
 // @Produce json
+// @Param include_archived query bool false "Inclui categorias arquivadas"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 200 {object} dto.PaginatedCategoriesResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -78,19 +120,20 @@ func (h *CategoryHandler) List(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	search := c.Query("search")
+	includeArchived := c.Query("include_archived") == "true"
 
-	categories, total, err := h.Service.ListCategories(userID, search, page, limit)
+	categories, total, err := h.Service.ListCategories(userID, utils.GetWorkspaceID(c), search, includeArchived, page, limit)
 	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
 		utils.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	var respCategories []dto.CategoryResponse
 	for _, cat := range categories {
-		respCategories = append(respCategories, dto.CategoryResponse{
-			ID:   cat.ID,
-			Name: cat.Name,
-		})
+		respCategories = append(respCategories, toCategoryResponse(&cat))
 	}
 
 	resp := dto.PaginatedCategoriesResponse{
@@ -104,16 +147,111 @@ func (h *CategoryHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// @BasePath /api/v1
+// @Summary Árvore de categorias
+// @Description Lista as categorias do workspace aninhadas por categoria pai. Sem include_archived=true, categorias arquivadas e suas subcategorias ficam de fora.
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param include_archived query bool false "Inclui categorias arquivadas"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {array} dto.CategoryTreeResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /categories/tree [get]
+func (h *CategoryHandler) Tree(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	tree, err := h.Service.CategoryTree(userID, utils.GetWorkspaceID(c), c.Query("include_archived") == "true")
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// @BasePath /api/v1
+// @Summary Lista as categorias modelo
+// @Description Lista, por idioma, as categorias modelo de receitas e despesas
+// @Tags category
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.CategoryTemplateResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /categories/templates [get]
+func (h *CategoryHandler) ListTemplates(c *gin.Context) {
+	if _, err := utils.GetUserID(c); err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, h.Service.ListTemplates())
+}
+
+// @BasePath /api/v1
+// @Summary Aplica categorias modelo
+// @Description Cria no workspace as categorias modelo do idioma (todas ou apenas as informadas em names). Categorias com nome já existente são ignoradas.
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param templates body dto.CategoryTemplateInput true "Idioma e categorias escolhidas"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.ApplyCategoryTemplatesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /categories/templates/apply [post]
+func (h *CategoryHandler) ApplyTemplates(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.CategoryTemplateInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	resp, err := h.Service.ApplyTemplates(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if respondCategoryError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // @BasePath /api/v1
 // @Summary Atualiza uma categoria
-// @Description Atualiza uma categoria do usuário
+// @Description Atualiza uma categoria do usuário. Retorna 409 se o novo tipo não aceita transações ou recorrências já existentes na categoria.
 // @Tags category
 // @Accept json
 // @Produce json
 // @Param id path int true "ID da categoria"
+// @Param category body dto.CategoryInput true "Dados da categoria (sem parent_id a categoria vira raiz)"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 200 {object} dto.CategoryResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /categories/{id} [put]
@@ -136,8 +274,14 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		return
 	}
 
-	category, err := h.Service.UpdateCategory(userID, id, input.Name)
+	category, err := h.Service.UpdateCategory(userID, utils.GetWorkspaceID(c), id, input)
 	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if respondCategoryError(c, err) {
+			return
+		}
 		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
 			return
 		}
@@ -145,25 +289,90 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		return
 	}
 
-	resp := dto.CategoryResponse{
-		ID:   category.ID,
-		Name: category.Name,
+	c.JSON(http.StatusOK, toCategoryResponse(category))
+}
+
+// @BasePath /api/v1
+// @Summary Arquiva uma categoria
+// @Description A categoria some das listagens e não aceita novas transações, mas continua válida nas transações existentes
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da categoria"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /categories/{id}/archive [post]
+func (h *CategoryHandler) Archive(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// @BasePath /api/v1
+// @Summary Desarquiva uma categoria
+// @Description Volta a exibir a categoria nas listagens e a aceitar novas transações
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da categoria"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /categories/{id}/unarchive [post]
+func (h *CategoryHandler) Unarchive(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *CategoryHandler) setArchived(c *gin.Context, archived bool) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	category, err := h.Service.SetArchived(userID, utils.GetWorkspaceID(c), id, archived)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toCategoryResponse(category))
 }
 
 // @BasePath /api/v1
 // @Summary Deleta uma categoria
-// @Description Deleta a categoria em questão
+// @Description Deleta a categoria em questão. Com reassign_to, as transações, recorrências e orçamentos são movidos para a categoria de destino antes; sem ele, a remoção é recusada (409) se a categoria tiver transações, recorrências ou orçamentos. Também retorna 409 se o destino não aceita o tipo das transações movidas ou já tem orçamento no mesmo mês inicial de um orçamento movido. As subcategorias viram categorias raiz.
 // @Tags category
 // @Accept json
 // @Produce json
 // @Param id path int true "ID da categoria"
+// @Param reassign_to query int false "ID da categoria que recebe as transações"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 204
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /categories/{id} [delete]
@@ -181,7 +390,24 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteCategory(id, userID); err != nil {
+	var reassignTo *uint
+	if param := c.Query("reassign_to"); param != "" {
+		target, err := strconv.ParseUint(param, 10, 64)
+		if err != nil || target == 0 {
+			utils.RespondError(c, http.StatusBadRequest, "reassign_to inválido")
+			return
+		}
+		targetID := uint(target)
+		reassignTo = &targetID
+	}
+
+	if err := h.Service.DeleteCategory(userID, utils.GetWorkspaceID(c), id, reassignTo); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if respondCategoryError(c, err) {
+			return
+		}
 		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
 			return
 		}
@@ -191,3 +417,57 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// @BasePath /api/v1
+// @Summary Mescla categorias
+// @Description Move transações, recorrências, orçamentos e subcategorias para a categoria de destino e remove a categoria de origem. Retorna 409 se o destino não aceita o tipo de alguma transação ou recorrência movida ou já tem orçamento no mesmo mês inicial de um orçamento movido.
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da categoria de origem"
+// @Param merge body dto.CategoryMergeInput true "Categoria de destino"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /categories/{id}/merge [post]
+func (h *CategoryHandler) Merge(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.CategoryMergeInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	category, err := h.Service.MergeCategory(userID, utils.GetWorkspaceID(c), id, input.TargetID)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if respondCategoryError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toCategoryResponse(category))
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Tamanho máximo do arquivo importado (5 MB)
+const maxImportFileSize = 5 << 20
+
+type ImportHandler struct {
+	Service *services.ImportService
+}
+
+func NewImportHandler(service *services.ImportService) *ImportHandler {
+	return &ImportHandler{Service: service}
+}
+
+// @BasePath /api/v1
+// @Summary Importa transações de um CSV
+// @Description Lê o CSV conforme o mapeamento de colunas e retorna uma pré-visualização com os erros de cada linha. Com commit=true, insere todas as linhas em uma única transação e atualiza o saldo da conta uma vez.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Arquivo CSV"
+// @Param account_id formData int true "ID da conta de destino"
+// @Param commit formData bool false "Grava as transações (padrão: false, apenas pré-visualização)"
+// @Param delimiter formData string false "Delimitador de colunas (padrão: ,)"
+// @Param has_header formData bool false "Primeira linha é cabeçalho (padrão: true)"
+// @Param date_column formData string true "Coluna da data (nome ou índice)"
+// @Param date_format formData string false "Formato da data, ex: DD/MM/YYYY (padrão), YYYY-MM-DD"
+// @Param amount_column formData string true "Coluna do valor (nome ou índice)"
+// @Param amount_sign formData string false "negative_expense (padrão): negativo é despesa; positive_expense: positivo é despesa"
+// @Param decimal_separator formData string false "Separador decimal: . (padrão) ou , (ex: 1.234,56)"
+// @Param description_column formData string false "Coluna da descrição (nome ou índice)"
+// @Param category_column formData string false "Coluna com o nome da categoria (nome ou índice)"
+// @Param default_category_id formData int false "Categoria usada quando a linha não tem categoria reconhecida"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.ImportResponse
+// @Success 201 {object} dto.ImportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ImportResponse
+// @Security BearerAuth
+// @Router /imports/csv [post]
+func (h *ImportHandler) ImportCSV(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.CSVImportInput
+	if err := c.ShouldBind(&input); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Dados inválidos")
+		return
+	}
+
+	file, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.Service.ImportCSV(userID, utils.GetWorkspaceID(c), input, file)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrImportInvalidRows) {
+			c.JSON(http.StatusUnprocessableEntity, resp)
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if resp.Committed {
+		status = http.StatusCreated
+	}
+	c.JSON(status, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Importa um extrato OFX
+// @Description Importa as transações (STMTTRN) de um extrato OFX 1.x (SGML) ou 2.x (XML). Transações com FITID já importado na conta são ignoradas.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Arquivo OFX"
+// @Param account_id formData int true "ID da conta de destino"
+// @Param default_category_id formData int true "Categoria atribuída às transações importadas"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.OFXImportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /imports/ofx [post]
+func (h *ImportHandler) ImportOFX(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.OFXImportInput
+	if err := c.ShouldBind(&input); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Dados inválidos")
+		return
+	}
+
+	file, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.Service.ImportOFX(userID, utils.GetWorkspaceID(c), input, file)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Abre o arquivo enviado no campo "file", respondendo erro se ausente ou grande demais
+func openImportFile(c *gin.Context) (multipart.File, bool) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "arquivo não enviado")
+		return nil, false
+	}
+	if fileHeader.Size > maxImportFileSize {
+		utils.RespondError(c, http.StatusBadRequest, "arquivo excede o tamanho máximo de 5 MB")
+		return nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "não foi possível ler o arquivo")
+		return nil, false
+	}
+
+	return file, true
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringHandler struct {
+	Service *services.RecurringService
+}
+
+func NewRecurringHandler(service *services.RecurringService) *RecurringHandler {
+	return &RecurringHandler{Service: service}
+}
+
+func toRecurringResponse(rule *models.RecurringTransaction) dto.RecurringTransactionResponse {
+	return dto.RecurringTransactionResponse{
+		ID:          rule.ID,
+		AccountID:   rule.AccountID,
+		CategoryID:  rule.CategoryID,
+		Type:        rule.Type,
+		Amount:      rule.Amount,
+		Description: rule.Description,
+		Frequency:   rule.Frequency,
+		Interval:    rule.Interval,
+		DayOfMonth:  rule.DayOfMonth,
+		StartDate:   rule.StartDate,
+		EndDate:     rule.EndDate,
+		Count:       rule.Count,
+		Occurrences: rule.Occurrences,
+		NextRunDate: rule.NextRunDate,
+		Active:      rule.Active,
+		LastError:   rule.LastError,
+		FailedAt:    rule.FailedAt,
+		CreatedAt:   rule.CreatedAt,
+	}
+}
+
+// @BasePath /api/v1
+// @Summary Cria uma transação recorrente
+// @Description Cria uma regra que gera transações automaticamente (diária, semanal, mensal ou anual). A data inicial pode estar no máximo 366 dias no passado.
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param recurring body dto.RecurringTransactionParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 201 {object} dto.RecurringTransactionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /recurring-transactions [post]
+func (h *RecurringHandler) Create(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.RecurringTransactionInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	rule, err := h.Service.CreateRecurring(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, toRecurringResponse(rule))
+}
+
+// @BasePath /api/v1
+// @Summary Lista as transações recorrentes
+// @Description Lista as regras de transações recorrentes do usuário. last_error e failed_at indicam a última ocorrência que não pôde ser gerada (ela é pulada, ou a regra é desativada se a falha exigir correção).
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {array} dto.RecurringTransactionResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /recurring-transactions [get]
+func (h *RecurringHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	rules, err := h.Service.ListRecurring(userID, utils.GetWorkspaceID(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := []dto.RecurringTransactionResponse{}
+	for i := range rules {
+		resp = append(resp, toRecurringResponse(&rules[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Retorna uma transação recorrente
+// @Description Retorna uma regra de transação recorrente do usuário
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da recorrência"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.RecurringTransactionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /recurring-transactions/{id} [get]
+func (h *RecurringHandler) Retrieve(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	rule, err := h.Service.RetrieveRecurring(userID, utils.GetWorkspaceID(c), id)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toRecurringResponse(rule))
+}
+
+// @BasePath /api/v1
+// @Summary Atualiza uma transação recorrente
+// @Description Atualiza uma regra recorrente; as ocorrências já geradas são mantidas
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da recorrência"
+// @Param recurring body dto.RecurringTransactionParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.RecurringTransactionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /recurring-transactions/{id} [put]
+func (h *RecurringHandler) Update(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.RecurringTransactionInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	rule, err := h.Service.UpdateRecurring(userID, utils.GetWorkspaceID(c), id, input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toRecurringResponse(rule))
+}
+
+// @BasePath /api/v1
+// @Summary Deleta uma transação recorrente
+// @Description Deleta uma regra recorrente; as transações já geradas são mantidas
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da recorrência"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /recurring-transactions/{id} [delete]
+func (h *RecurringHandler) Delete(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteRecurring(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	Service *services.ReportService
+}
+
+func NewReportHandler(service *services.ReportService) *ReportHandler {
+	return &ReportHandler{Service: service}
+}
+
+// @BasePath /api/v1
+// @Summary Resumo de receitas e despesas
+// @Description Soma e conta as transações por período, opcionalmente detalhando por categoria ou tipo. Com split_by=category os gastos das subcategorias são somados na categoria raiz; use subcategory para detalhar cada categoria. Aceita os mesmos filtros da listagem de transações. A série tem no máximo 400 períodos (intervalos maiores retornam 400).
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param group_by query string false "day, week, month (padrão) ou year"
+// @Param split_by query string false "category, subcategory ou type"
+// @Param from_date query string false "Data inicial (YYYY-MM-DD)"
+// @Param to_date query string false "Data final (YYYY-MM-DD)"
+// @Param category_id query int false "ID da categoria (inclui subcategorias)"
+// @Param min_amount query number false "Valor mínimo"
+// @Param max_amount query number false "Valor máximo"
+// @Param type query string false "income ou expense"
+// @Param q query string false "Texto buscado na descrição"
+// @Param tags query string false "Etiquetas separadas por vírgula"
+// @Param tag_mode query string false "any (padrão): qualquer etiqueta; all: todas"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.ReportSummaryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /reports/summary [get]
+func (h *ReportHandler) Summary(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	filter := parseTransactionFilter(c)
+
+	resp, err := h.Service.Summary(userID, utils.GetWorkspaceID(c), filter, c.Query("group_by"), c.Query("split_by"))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Totais por etiqueta
+// @Description Soma receitas e despesas de cada etiqueta. Uma transação com várias etiquetas entra no total de cada uma. Aceita os mesmos filtros da listagem de transações.
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param from_date query string false "Data inicial (YYYY-MM-DD)"
+// @Param to_date query string false "Data final (YYYY-MM-DD)"
+// @Param category_id query int false "ID da categoria (inclui subcategorias)"
+// @Param min_amount query number false "Valor mínimo"
+// @Param max_amount query number false "Valor máximo"
+// @Param type query string false "income ou expense"
+// @Param q query string false "Texto buscado na descrição"
+// @Param tags query string false "Etiquetas separadas por vírgula"
+// @Param tag_mode query string false "any (padrão): qualquer etiqueta; all: todas"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.TagReportResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /reports/tags [get]
+func (h *ReportHandler) Tags(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	resp, err := h.Service.TagTotals(userID, utils.GetWorkspaceID(c), parseTransactionFilter(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	Service *services.SessionService
+}
+
+func NewSessionHandler(service *services.SessionService) *SessionHandler {
+	return &SessionHandler{Service: service}
+}
+
+// @BasePath /api/v1
+// @Summary Lista as sessões ativas
+// @Description Lista os dispositivos em que o usuário está logado, indicando a sessão atual
+// @Tags session
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.SessionResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	sessions, err := h.Service.ListSessions(userID)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	currentID, _ := c.Get("session_id")
+
+	resp := []dto.SessionResponse{}
+	for _, session := range sessions {
+		resp = append(resp, dto.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			Current:    currentID == session.ID,
+			LastSeenAt: session.LastSeenAt,
+			CreatedAt:  session.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Encerra uma sessão
+// @Description Encerra a sessão de um dispositivo: seus refresh tokens são revogados e os access tokens deixam de ser aceitos
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da sessão"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.RevokeSession(userID, id); err != nil {
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SplitHandler struct {
+	Service *services.SplitService
+}
+
+func NewSplitHandler(service *services.SplitService) *SplitHandler {
+	return &SplitHandler{Service: service}
+}
+
+func toParticipantResponse(participant *models.Participant) dto.ParticipantResponse {
+	return dto.ParticipantResponse{
+		ID:        participant.ID,
+		Name:      participant.Name,
+		UserID:    participant.UserID,
+		CreatedAt: participant.CreatedAt,
+	}
+}
+
+func toSettlementResponse(settlement *models.Settlement) dto.SettlementResponse {
+	return dto.SettlementResponse{
+		ID:                settlement.ID,
+		FromParticipantID: settlement.FromParticipantID,
+		FromName:          settlement.FromParticipant.Name,
+		ToParticipantID:   settlement.ToParticipantID,
+		ToName:            settlement.ToParticipant.Name,
+		Amount:            settlement.Amount,
+		Date:              settlement.Date,
+		Note:              settlement.Note,
+		CreatedAt:         settlement.CreatedAt,
+	}
+}
+
+// @BasePath /api/v1
+// @Summary Cria um participante
+// @Description Cria uma pessoa com quem as despesas do workspace podem ser divididas, opcionalmente vinculada a um membro
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param participant body dto.ParticipantInput true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 201 {object} dto.ParticipantResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /participants [post]
+func (h *SplitHandler) CreateParticipant(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.ParticipantInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	participant, err := h.Service.CreateParticipant(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrParticipantExists) {
+			utils.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, toParticipantResponse(participant))
+}
+
+// @BasePath /api/v1
+// @Summary Lista os participantes
+// @Description Lista as pessoas com quem as despesas do workspace podem ser divididas
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {array} dto.ParticipantResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /participants [get]
+func (h *SplitHandler) ListParticipants(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	participants, err := h.Service.ListParticipants(userID, utils.GetWorkspaceID(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := []dto.ParticipantResponse{}
+	for i := range participants {
+		resp = append(resp, toParticipantResponse(&participants[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Deleta um participante
+// @Description Deleta um participante que não tenha divisões nem pagamentos registrados
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do participante"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /participants/{id} [delete]
+func (h *SplitHandler) DeleteParticipant(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteParticipant(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		if errors.Is(err, services.ErrParticipantInUse) {
+			utils.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @BasePath /api/v1
+// @Summary Registra um pagamento entre participantes
+// @Description Registra que um participante pagou outro, quitando (total ou parcialmente) a dívida entre eles
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param settlement body dto.SettlementInput true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 201 {object} dto.SettlementResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /settlements [post]
+func (h *SplitHandler) CreateSettlement(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.SettlementInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	settlement, err := h.Service.CreateSettlement(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, toSettlementResponse(settlement))
+}
+
+// @BasePath /api/v1
+// @Summary Lista os pagamentos entre participantes
+// @Description Lista os pagamentos registrados para quitar dívidas de despesas divididas
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param page query int false "Página"
+// @Param limit query int false "Itens por página"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.PaginatedSettlementResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /settlements [get]
+func (h *SplitHandler) ListSettlements(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	// Query params
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil && val > 0 {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 100 {
+			limit = val
+		}
+	}
+
+	settlements, total, err := h.Service.ListSettlements(userID, utils.GetWorkspaceID(c), page, limit)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respSettlements := []dto.SettlementResponse{}
+	for i := range settlements {
+		respSettlements = append(respSettlements, toSettlementResponse(&settlements[i]))
+	}
+
+	resp := dto.PaginatedSettlementResponse{
+		Data:       respSettlements,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Deleta um pagamento entre participantes
+// @Description Deleta um pagamento registrado, reabrindo a dívida correspondente
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do pagamento"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /settlements/{id} [delete]
+func (h *SplitHandler) DeleteSettlement(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteSettlement(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @BasePath /api/v1
+// @Summary Quem deve a quem
+// @Description Calcula, a partir das despesas divididas e dos pagamentos, a dívida líquida entre cada par de participantes e o saldo de cada um
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.SplitLedgerResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /splits/ledger [get]
+func (h *SplitHandler) Ledger(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	resp, err := h.Service.Ledger(userID, utils.GetWorkspaceID(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Simplifica as dívidas
+// @Description Sugere o menor conjunto de pagamentos que quita todas as dívidas entre os participantes
+// @Tags split
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.SimplifiedDebtsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /splits/simplify [get]
+func (h *SplitHandler) Simplify(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	resp, err := h.Service.SimplifyDebts(userID, utils.GetWorkspaceID(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	Service *services.TagService
+}
+
+func NewTagHandler(service *services.TagService) *TagHandler {
+	return &TagHandler{Service: service}
+}
+
+// Responde os erros de validação de etiquetas. Retorna false se o erro não for desse tipo.
+func respondTagError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrTagNameEmpty):
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrTagExists):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// @BasePath /api/v1
+// @Summary Cria uma etiqueta
+// @Description Cria uma etiqueta para agrupar transações de categorias diferentes
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param tag body dto.TagInput true "Nome da etiqueta"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 201 {object} dto.TagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /tags [post]
+func (h *TagHandler) Create(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.TagInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	tag, err := h.Service.CreateTag(userID, utils.GetWorkspaceID(c), input.Name)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if respondTagError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.TagResponse{ID: tag.ID, Name: tag.Name})
+}
+
+// @BasePath /api/v1
+// @Summary Lista as etiquetas
+// @Description Lista as etiquetas do workspace em ordem alfabética
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {array} dto.TagResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	tags, err := h.Service.ListTags(userID, utils.GetWorkspaceID(c))
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toTagResponses(tags))
+}
+
+// @BasePath /api/v1
+// @Summary Renomeia uma etiqueta
+// @Description Renomeia a etiqueta em todas as transações
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da etiqueta"
+// @Param tag body dto.TagInput true "Novo nome da etiqueta"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.TagResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /tags/{id} [put]
+func (h *TagHandler) Update(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.TagInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	tag, err := h.Service.UpdateTag(userID, utils.GetWorkspaceID(c), id, input.Name)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if respondTagError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TagResponse{ID: tag.ID, Name: tag.Name})
+}
+
+// @BasePath /api/v1
+// @Summary Deleta uma etiqueta
+// @Description Deleta a etiqueta e a remove das transações (as transações são mantidas)
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da etiqueta"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteTag(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/exporter"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
 	"github.com/daviolvr/Fintrack/internal/services"
 	"github.com/daviolvr/Fintrack/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -19,13 +26,38 @@ func NewTransactionHandler(service *services.TransactionService) *TransactionHan
 	return &TransactionHandler{Service: service}
 }
 
+func toTagResponses(tags []models.Tag) []dto.TagResponse {
+	resp := make([]dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		resp = append(resp, dto.TagResponse{ID: tag.ID, Name: tag.Name})
+	}
+	return resp
+}
+
+func toTransactionSplits(splits []models.TransactionSplit) []dto.TransactionSplitResponse {
+	if len(splits) == 0 {
+		return nil
+	}
+
+	resp := make([]dto.TransactionSplitResponse, 0, len(splits))
+	for _, split := range splits {
+		resp = append(resp, dto.TransactionSplitResponse{
+			ParticipantID: split.ParticipantID,
+			Name:          split.Participant.Name,
+			Amount:        split.Amount,
+		})
+	}
+	return resp
+}
+
 // @BasePath /api/v1
 // @Summary Cria uma transação
-// @Description Cria uma transação para o usuário em questão
+// @Description Cria uma transação para o usuário em questão. Despesas podem ser divididas entre participantes (split); os relatórios consideram apenas a parte de quem pagou.
 // @Tags transaction
 // @Accept json
 // @Produce json
 // @Param transaction body dto.TransactionCreateParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 201 {object} dto.TransactionCreateResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 400 {object} dto.ErrorResponse
@@ -44,18 +76,27 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.Service.CreateTransaction(userID, input.CategoryID, input.Type, input.Amount, input.Description, input.Date)
+	tx, err := h.Service.CreateTransaction(userID, utils.GetWorkspaceID(c), input)
 	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
 		utils.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	resp := dto.TransactionCreateResponse{
+		ID:          tx.ID,
+		AccountID:   tx.AccountID,
 		CategoryID:  tx.CategoryID,
 		Type:        tx.Type,
 		Amount:      tx.Amount,
 		Description: tx.Description,
 		Date:        tx.Date,
+		SplitType:   tx.SplitType,
+		ShareAmount: tx.ShareAmount,
+		Splits:      toTransactionSplits(tx.Splits),
+		Tags:        toTagResponses(tx.Tags),
 	}
 
 	c.JSON(http.StatusCreated, resp)
@@ -68,6 +109,7 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID da transação"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 200 {object} dto.TransactionResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
@@ -88,18 +130,27 @@ func (h *TransactionHandler) Retrieve(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.Service.RetrieveTransaction(userID, id)
+	tx, err := h.Service.RetrieveTransaction(userID, utils.GetWorkspaceID(c), id)
 	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
 		utils.RespondError(c, http.StatusNotFound, err.Error())
 		return
 	}
 
 	resp := dto.TransactionResponse{
+		ID:          tx.ID,
+		AccountID:   tx.AccountID,
 		CategoryID:  tx.CategoryID,
 		Type:        tx.Type,
 		Amount:      tx.Amount,
 		Description: tx.Description,
 		Date:        tx.Date,
+		SplitType:   tx.SplitType,
+		ShareAmount: tx.ShareAmount,
+		Splits:      toTransactionSplits(tx.Splits),
+		Tags:        toTagResponses(tx.Tags),
 		CreatedAt:   tx.CreatedAt,
 		UpdatedAt:   tx.UpdatedAt,
 	}
@@ -109,10 +160,18 @@ func (h *TransactionHandler) Retrieve(c *gin.Context) {
 
 // @BasePath /api/v1
 // @Summary Lista as transações
-// @Description Lista as transações de um usuário
+// @Description Lista as transações de um usuário. Com q, busca na descrição (texto completo em português, sem acentos, ou trecho de palavra), ordena por relevância (se sort não for informado) e devolve a descrição com os termos marcados em highlight.
+// @Description A paginação pode ser por page/limit ou por cursor: next_cursor e prev_cursor apontam para as páginas vizinhas e, enviados em cursor, mantêm a ordenação original mesmo com novas transações (page vem 0 nesse modo).
 // @Tags transaction
 // @Accept json
 // @Produce json
+// @Param q query string false "Texto buscado na descrição"
+// @Param sort query string false "Campo de ordenação: date (padrão), amount ou created_at"
+// @Param order query string false "Direção da ordenação: asc ou desc (padrão)"
+// @Param cursor query string false "Cursor de next_cursor/prev_cursor (ignora page, sort e order)"
+// @Param page query int false "Página (padrão: 1)"
+// @Param limit query int false "Itens por página (padrão: 10, máximo: 100)"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 200 {object} dto.PaginatedTransactionResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 400 {object} dto.ErrorResponse
@@ -140,57 +199,39 @@ func (h *TransactionHandler) List(c *gin.Context) {
 		}
 	}
 
-	var fromDatePtr, toDatePtr *time.Time
-	if from := c.Query("from_date"); from != "" {
-		if t, err := time.Parse("2006-01-02", from); err == nil {
-			fromDatePtr = &t
-		}
-	}
-	if to := c.Query("to_date"); to != "" {
-		if t, err := time.Parse("2006-01-02", to); err == nil {
-			toDatePtr = &t
-		}
-	}
+	filter := parseTransactionFilter(c)
 
-	var categoryIDPtr *uint
-	if cat := c.Query("category_id"); cat != "" {
-		if id, err := strconv.ParseUint(cat, 10, 64); err == nil {
-			val := uint(id)
-			categoryIDPtr = &val
-		}
-	}
+	sort := dto.TransactionSort{Field: c.Query("sort"), Order: c.Query("order")}
+	cursor := c.Query("cursor")
 
-	var minAmountPtr, maxAmountPtr *float64
-	if min := c.Query("min_amount"); min != "" {
-		if val, err := strconv.ParseFloat(min, 64); err == nil {
-			minAmountPtr = &val
+	result, err := h.Service.ListTransactions(userID, utils.GetWorkspaceID(c), filter, sort, cursor, page, limit)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
 		}
-	}
-	if max := c.Query("max_amount"); max != "" {
-		if val, err := strconv.ParseFloat(max, 64); err == nil {
-			maxAmountPtr = &val
+		if errors.Is(err, services.ErrInvalidSort) || errors.Is(err, services.ErrInvalidCursor) {
+			utils.RespondError(c, http.StatusBadRequest, err.Error())
+			return
 		}
-	}
-
-	var typePtr *string
-	if t := c.Query("type"); t != "" {
-		typePtr = &t
-	}
-
-	txs, total, err := h.Service.ListTransactions(userID, fromDatePtr, toDatePtr, categoryIDPtr, minAmountPtr, maxAmountPtr, typePtr, page, limit)
-	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	var respTxs []dto.TransactionResponse
-	for _, tx := range txs {
+	for _, tx := range result.Transactions {
 		respTxs = append(respTxs, dto.TransactionResponse{
+			ID:          tx.ID,
+			AccountID:   tx.AccountID,
 			CategoryID:  tx.CategoryID,
 			Type:        tx.Type,
 			Amount:      tx.Amount,
 			Description: tx.Description,
 			Date:        tx.Date,
+			SplitType:   tx.SplitType,
+			ShareAmount: tx.ShareAmount,
+			Splits:      toTransactionSplits(tx.Splits),
+			Tags:        toTagResponses(tx.Tags),
+			Highlight:   tx.Highlight,
 			CreatedAt:   tx.CreatedAt,
 			UpdatedAt:   tx.UpdatedAt,
 		})
@@ -198,15 +239,94 @@ func (h *TransactionHandler) List(c *gin.Context) {
 
 	resp := dto.PaginatedTransactionResponse{
 		Data:       respTxs,
-		Total:      total,
+		Total:      result.Total,
 		Page:       page,
 		Limit:      limit,
-		TotalPages: (total + limit - 1) / limit,
+		TotalPages: (result.Total + limit - 1) / limit,
+		NextCursor: result.NextCursor,
+		PrevCursor: result.PrevCursor,
+	}
+	if cursor != "" {
+		resp.Page = 0
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// @BasePath /api/v1
+// @Summary Exporta as transações
+// @Description Exporta todas as transações que atendem aos filtros da listagem (sem paginação), com os nomes de conta e categoria
+// @Tags transaction
+// @Produce text/csv
+// @Produce json
+// @Produce application/x-ofx
+// @Param format query string false "Formato: csv (padrão), json ou ofx"
+// @Param from_date query string false "Data inicial (YYYY-MM-DD)"
+// @Param to_date query string false "Data final (YYYY-MM-DD)"
+// @Param category_id query int false "ID da categoria (inclui subcategorias)"
+// @Param min_amount query number false "Valor mínimo"
+// @Param max_amount query number false "Valor máximo"
+// @Param type query string false "income ou expense"
+// @Param q query string false "Texto buscado na descrição"
+// @Param tags query string false "Etiquetas separadas por vírgula"
+// @Param tag_mode query string false "any (padrão): qualquer etiqueta; all: todas"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /transactions/export [get]
+func (h *TransactionHandler) Export(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	filter := parseTransactionFilter(c)
+
+	out := &exportWriter{c: c}
+	enc, err := exporter.New(c.Query("format"), out, filter)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	out.filename = fmt.Sprintf("transacoes-%s.%s", time.Now().Format("2006-01-02"), enc.Extension())
+	out.contentType = enc.ContentType()
+
+	if err := h.Service.ExportTransactions(userID, utils.GetWorkspaceID(c), filter, enc); err != nil {
+		// Com o corpo já em envio não é mais possível alterar o status da resposta
+		if out.started {
+			log.Printf("Erro ao exportar transações do usuário %d: %v", userID, err)
+			c.Abort()
+			return
+		}
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// Só envia os cabeçalhos do arquivo na primeira escrita, para que erros
+// anteriores ao início da exportação ainda possam ser respondidos em JSON
+type exportWriter struct {
+	c           *gin.Context
+	filename    string
+	contentType string
+	started     bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
 // @BasePath /api/v1
 // @Summary Atualiza uma transação
 // @Description Atualiza uma transação do usuário em questão
@@ -214,6 +334,7 @@ func (h *TransactionHandler) List(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param transaction body dto.TransactionUpdateParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 200 {object} dto.TransactionResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 400 {object} dto.ErrorResponse
@@ -240,18 +361,27 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.Service.UpdateTransaction(userID, id, input.CategoryID, input.Type, input.Amount, input.Description, input.Date)
+	tx, err := h.Service.UpdateTransaction(userID, utils.GetWorkspaceID(c), id, input)
 	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
 		utils.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	resp := dto.TransactionResponse{
+		ID:          tx.ID,
+		AccountID:   tx.AccountID,
 		CategoryID:  tx.CategoryID,
 		Type:        tx.Type,
 		Amount:      tx.Amount,
 		Description: tx.Description,
 		Date:        tx.Date,
+		SplitType:   tx.SplitType,
+		ShareAmount: tx.ShareAmount,
+		Splits:      toTransactionSplits(tx.Splits),
+		Tags:        toTagResponses(tx.Tags),
 		CreatedAt:   tx.CreatedAt,
 		UpdatedAt:   tx.UpdatedAt,
 	}
@@ -259,6 +389,60 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// @BasePath /api/v1
+// @Summary Operações em lote
+// @Description Cria, altera (conta, categoria, descrição, data ou etiquetas das transações selecionadas por ids ou filter) e remove transações em uma única transação do banco, nesta ordem. O saldo de cada conta é ajustado uma única vez com o efeito líquido do lote.
+// @Description Retorna o resultado de cada item. Sem partial, qualquer item com erro desfaz o lote inteiro (422); com partial=true, apenas os itens com erro são descartados. O saldo é conferido item a item: o item que deixaria uma conta negativa (exceto cartão de crédito) falha.
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param operations body dto.TransactionBulkInput true "Operações do lote"
+// @Param partial query bool false "Aplica os itens válidos mesmo que outros falhem"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.TransactionBulkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.TransactionBulkResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /transactions/bulk [post]
+func (h *TransactionHandler) Bulk(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.TransactionBulkInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+	input.Partial = c.Query("partial") == "true"
+
+	resp, err := h.Service.BulkTransactions(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrBulkFailed) {
+			c.JSON(http.StatusUnprocessableEntity, resp)
+			return
+		}
+		if errors.Is(err, services.ErrBulkEmpty) ||
+			errors.Is(err, services.ErrBulkNoSelection) ||
+			errors.Is(err, services.ErrBulkNoChanges) ||
+			errors.Is(err, services.ErrBulkTooLarge) ||
+			errors.Is(err, services.ErrBulkInvalidDate) {
+			utils.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // @BasePath /api/v1
 // @Summary Deleta uma transação
 // @Description Deleta uma transação do usuário em questão
@@ -266,6 +450,7 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID da transação"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
 // @Success 204
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 400 {object} dto.ErrorResponse
@@ -287,10 +472,75 @@ func (h *TransactionHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.DeleteTransaction(userID, id); err != nil {
+	if err := h.Service.DeleteTransaction(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
 		utils.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// Lê os filtros opcionais de transações da query string (valores inválidos são ignorados)
+func parseTransactionFilter(c *gin.Context) dto.TransactionFilter {
+	var filter dto.TransactionFilter
+
+	if from := c.Query("from_date"); from != "" {
+		if t, err := time.Parse("2006-01-02", from); err == nil {
+			filter.FromDate = &t
+		}
+	}
+	if to := c.Query("to_date"); to != "" {
+		if t, err := time.Parse("2006-01-02", to); err == nil {
+			filter.ToDate = &t
+		}
+	}
+
+	if cat := c.Query("category_id"); cat != "" {
+		if id, err := strconv.ParseUint(cat, 10, 64); err == nil {
+			val := uint(id)
+			filter.CategoryID = &val
+		}
+	}
+
+	if min := c.Query("min_amount"); min != "" {
+		if val, err := money.Parse(min); err == nil {
+			filter.MinAmount = &val
+		}
+	}
+	if max := c.Query("max_amount"); max != "" {
+		if val, err := money.Parse(max); err == nil {
+			filter.MaxAmount = &val
+		}
+	}
+
+	if t := c.Query("type"); t != "" {
+		filter.Type = &t
+	}
+
+	// Busca textual na descrição (limitada a 200 caracteres)
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		if runes := []rune(q); len(runes) > 200 {
+			q = string(runes[:200])
+		}
+		filter.Query = q
+	}
+
+	if tags := c.Query("tags"); tags != "" {
+		seen := make(map[string]bool)
+		for _, tag := range strings.Split(tags, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+		if c.Query("tag_mode") == "all" {
+			filter.TagMode = "all"
+		}
+	}
+
+	return filter
+}
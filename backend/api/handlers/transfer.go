@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandler struct {
+	Service *services.TransferService
+}
+
+func NewTransferHandler(service *services.TransferService) *TransferHandler {
+	return &TransferHandler{Service: service}
+}
+
+// @BasePath /api/v1
+// @Summary Cria uma transferência
+// @Description Transfere um valor entre duas contas do usuário, sem afetar receitas e despesas
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param transfer body dto.TransferCreateParam true "Request body"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 201 {object} dto.TransferResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers [post]
+func (h *TransferHandler) Create(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.TransferInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	transfer, err := h.Service.CreateTransfer(userID, utils.GetWorkspaceID(c), input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := dto.TransferResponse{
+		ID:            transfer.ID,
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		Description:   transfer.Description,
+		Date:          transfer.Date,
+		CreatedAt:     transfer.CreatedAt,
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Lista as transferências
+// @Description Lista as transferências entre contas do usuário
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param page query int false "Página"
+// @Param limit query int false "Itens por página"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 200 {object} dto.PaginatedTransferResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers [get]
+func (h *TransferHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	// Query params
+	page := 1
+	limit := 10
+	if p := c.Query("page"); p != "" {
+		if val, err := strconv.Atoi(p); err == nil && val > 0 {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 100 {
+			limit = val
+		}
+	}
+
+	transfers, total, err := h.Service.ListTransfers(userID, utils.GetWorkspaceID(c), page, limit)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respTransfers := []dto.TransferResponse{}
+	for _, t := range transfers {
+		respTransfers = append(respTransfers, dto.TransferResponse{
+			ID:            t.ID,
+			FromAccountID: t.FromAccountID,
+			ToAccountID:   t.ToAccountID,
+			Amount:        t.Amount,
+			Description:   t.Description,
+			Date:          t.Date,
+			CreatedAt:     t.CreatedAt,
+		})
+	}
+
+	resp := dto.PaginatedTransferResponse{
+		Data:       respTransfers,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: h.Service.TotalPages(total, limit),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Deleta uma transferência
+// @Description Deleta uma transferência, devolvendo o valor à conta de origem
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da transferência"
+// @Param X-Workspace-ID header int false "ID do workspace (padrão: workspace pessoal)"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/{id} [delete]
+func (h *TransferHandler) Delete(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteTransfer(userID, utils.GetWorkspaceID(c), id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	Service *services.TwoFactorService
+}
+
+func NewTwoFactorHandler(service *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{Service: service}
+}
+
+// @BasePath /api/v1
+// @Summary Inicia a configuração do 2FA
+// @Description Gera um segredo TOTP e a URI otpauth:// para cadastrar no app autenticador. O 2FA só é ativado após a confirmação.
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.TwoFactorSetupResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	resp, err := h.Service.Setup(userID)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Confirma e ativa o 2FA
+// @Description Valida o primeiro código do app autenticador, ativa o 2FA e retorna os códigos de recuperação (exibidos uma única vez)
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param code body dto.TwoFactorCodeInput true "Request body"
+// @Success 200 {object} dto.TwoFactorRecoveryCodesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.TwoFactorCodeInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	codes, err := h.Service.Confirm(userID, input.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// @BasePath /api/v1
+// @Summary Desativa o 2FA
+// @Description Desativa o 2FA do usuário. Exige a senha e um código TOTP ou de recuperação.
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorDisableInput true "Request body"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.TwoFactorDisableInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.Disable(userID, input.Password, input.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	utils.RespondMessage(c, "Autenticação em dois fatores desativada")
+}
+
+// @BasePath /api/v1
+// @Summary Gera novos códigos de recuperação
+// @Description Invalida os códigos de recuperação anteriores e gera novos. Exige um código TOTP ou de recuperação.
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param code body dto.TwoFactorCodeInput true "Request body"
+// @Success 200 {object} dto.TwoFactorRecoveryCodesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.TwoFactorCodeInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	codes, err := h.Service.RegenerateRecoveryCodes(userID, input.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Converte os erros do serviço de 2FA em status HTTP
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrIncorrectPassword):
+		utils.RespondError(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorNotStarted),
+		errors.Is(err, services.ErrInvalidTwoFactorCode):
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+	default:
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+	}
+}
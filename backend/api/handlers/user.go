@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/daviolvr/Fintrack/internal/dto"
@@ -19,7 +20,7 @@ func NewUserHandler(service *services.UserService) *UserHandler {
 
 // @BasePath /api/v1
 // @Summary Retorna dados do usuário
-// @Description Retorna os dados do usuário em questão
+// @Description Retorna os dados do usuário em questão, com o saldo total das contas
 // @Tags user
 // @Accept json
 // @Produce json
@@ -41,11 +42,19 @@ func (h *UserHandler) Me(c *gin.Context) {
 		return
 	}
 
+	// Saldo total entre todas as contas
+	balance, err := h.Service.GetTotalBalance(userID)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, utils.ErrInternalServer.Error())
+		return
+	}
+
 	resp := dto.UserMeResponse{
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
 		Email:     user.Email,
-		Balance:   user.Balance,
+		Balance:   balance,
+		TwoFactor: user.TOTPEnabled,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -92,46 +101,15 @@ func (h *UserHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-// @BasePath /api/v1
-// @Summary Atualiza o saldo de um usuário
-// @Description Atualiza o saldo do usuário em questão
-// @Tags user
-// @Accept json
-// @Produce json
-// @Param data body dto.BalanceUpdateParam true "Novo saldo"
-// @Success 200 {object} dto.UserUpdateBalanceResponse
-// @Failure 401 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Security BearerAuth
-// @Router /users/me/balance [patch]
-func (h *UserHandler) UpdateBalance(c *gin.Context) {
-	userID, err := utils.GetUserID(c)
-	if err != nil {
-		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
-		return
-	}
-
-	var input dto.UserUpdateBalanceInput
-	if !utils.BindJSON(c, &input) {
-		return
-	}
-
-	if err := h.Service.UpdateBalance(userID, input.Balance); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusOK, dto.UserUpdateBalanceResponse(input))
-}
-
 // @BasePath /api/v1
 // @Summary Deleta um usuário
-// @Description Deleta o usuário em questão
+// @Description Deleta o usuário em questão e seus workspaces. Nos workspaces de outros donos, o que ele criou permanece sem autor. Retorna 409 se ele é dono de um workspace com outros membros.
 // @Tags user
 // @Accept json
 // @Produce json
 // @Success 204
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /users/me [delete]
@@ -148,6 +126,10 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.Service.DeleteUser(userID, input.Password); err != nil {
+		if errors.Is(err, services.ErrOwnsSharedWorkspace) {
+			utils.RespondError(c, http.StatusConflict, err.Error())
+			return
+		}
 		utils.RespondError(c, http.StatusUnauthorized, err.Error())
 		return
 	}
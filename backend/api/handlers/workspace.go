@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/services"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkspaceHandler struct {
+	Service *services.WorkspaceService
+}
+
+func NewWorkspaceHandler(service *services.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{Service: service}
+}
+
+// Responde os erros de acesso a workspace. Retorna false se o erro não for desse tipo.
+func respondWorkspaceError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrWorkspaceNotFound):
+		utils.RespondError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrWorkspaceForbidden):
+		utils.RespondError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrPersonalWorkspace),
+		errors.Is(err, services.ErrInvalidInvitation),
+		errors.Is(err, services.ErrInvitationEmail):
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrAlreadyWorkspaceMember):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+func toWorkspaceResponse(workspace *models.Workspace, role string) dto.WorkspaceResponse {
+	return dto.WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Personal:  workspace.Personal,
+		Role:      role,
+		CreatedAt: workspace.CreatedAt,
+	}
+}
+
+// @BasePath /api/v1
+// @Summary Cria um workspace
+// @Description Cria um workspace compartilhado tendo o usuário como dono
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param workspace body dto.WorkspaceInput true "Request body"
+// @Success 201 {object} dto.WorkspaceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces [post]
+func (h *WorkspaceHandler) Create(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.WorkspaceInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	workspace, err := h.Service.CreateWorkspace(userID, input)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, toWorkspaceResponse(workspace, services.RoleOwner))
+}
+
+// @BasePath /api/v1
+// @Summary Lista os workspaces
+// @Description Lista os workspaces dos quais o usuário participa, com o seu papel em cada um
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.WorkspaceResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces [get]
+func (h *WorkspaceHandler) List(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	workspaces, err := h.Service.ListWorkspaces(userID)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := []dto.WorkspaceResponse{}
+	for i := range workspaces {
+		resp = append(resp, toWorkspaceResponse(&workspaces[i].Workspace, workspaces[i].Role))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Exclui um workspace
+// @Description Exclui um workspace compartilhado e todos os seus dados. Apenas o dono; o workspace pessoal não pode ser excluído.
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do workspace"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id} [delete]
+func (h *WorkspaceHandler) Delete(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.DeleteWorkspace(userID, id); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, utils.ErrNotFound.Error()) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @BasePath /api/v1
+// @Summary Lista os membros de um workspace
+// @Description Lista os membros do workspace e seus papéis
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do workspace"
+// @Success 200 {array} dto.WorkspaceMemberResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id}/members [get]
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	members, err := h.Service.ListMembers(userID, id)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := []dto.WorkspaceMemberResponse{}
+	for _, member := range members {
+		resp = append(resp, dto.WorkspaceMemberResponse{
+			UserID:   member.UserID,
+			Name:     member.FirstName + " " + member.LastName,
+			Email:    member.Email,
+			Role:     member.Role,
+			JoinedAt: member.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @BasePath /api/v1
+// @Summary Altera o papel de um membro
+// @Description Altera o papel (editor ou viewer) de um membro do workspace. Apenas o dono.
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do workspace"
+// @Param user_id path int true "ID do membro"
+// @Param role body dto.WorkspaceMemberRoleInput true "Request body"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id}/members/{user_id} [put]
+func (h *WorkspaceHandler) UpdateMemberRole(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	paramMemberID, err := utils.GetIDParam(c, "user_id")
+	memberID := uint(paramMemberID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.WorkspaceMemberRoleInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Service.UpdateMemberRole(userID, id, memberID, input.Role); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, "Membro não encontrado") {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.RespondMessage(c, "Papel atualizado com sucesso")
+}
+
+// @BasePath /api/v1
+// @Summary Remove um membro
+// @Description Remove um membro do workspace. O dono remove qualquer membro; os demais podem apenas sair.
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do workspace"
+// @Param user_id path int true "ID do membro"
+// @Success 204
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id}/members/{user_id} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	paramMemberID, err := utils.GetIDParam(c, "user_id")
+	memberID := uint(paramMemberID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	if err := h.Service.RemoveMember(userID, id, memberID); err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		if utils.HandleNotFound(c, err, "Membro não encontrado") {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @BasePath /api/v1
+// @Summary Convida para um workspace
+// @Description Envia por email um convite (válido por 7 dias) para participar do workspace. Apenas o dono.
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do workspace"
+// @Param invitation body dto.WorkspaceInvitationInput true "Request body"
+// @Success 201 {object} dto.WorkspaceInvitationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/{id}/invitations [post]
+func (h *WorkspaceHandler) Invite(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	paramID, err := utils.GetIDParam(c, "id")
+	id := uint(paramID)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, utils.ErrInvalidID.Error())
+		return
+	}
+
+	var input dto.WorkspaceInvitationInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	invitation, err := h.Service.Invite(userID, id, input)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.WorkspaceInvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+	})
+}
+
+// @BasePath /api/v1
+// @Summary Aceita um convite
+// @Description Aceita um convite para workspace. O email do usuário autenticado precisa ser o email convidado.
+// @Tags workspace
+// @Accept json
+// @Produce json
+// @Param invitation body dto.AcceptInvitationInput true "Request body"
+// @Success 200 {object} dto.WorkspaceResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /workspaces/invitations/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+		return
+	}
+
+	var input dto.AcceptInvitationInput
+	if !utils.BindJSON(c, &input) {
+		return
+	}
+
+	workspace, role, err := h.Service.AcceptInvitation(userID, input.Token)
+	if err != nil {
+		if respondWorkspaceError(c, err) {
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkspaceResponse(workspace, role))
+}
@@ -9,10 +9,35 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// Prefixo das chaves de API pessoais
+const apiKeyPrefix = "fk_"
+
+// Valida chaves de API, retornando o usuário dono e o escopo ("read" ou "write")
+type APIKeyAuthenticator interface {
+	Authenticate(key string) (uint, string, error)
+}
+
+// Confere se a sessão de login vinculada ao access token ainda está ativa
+type SessionValidator interface {
+	ValidateSession(sessionID, userID uint) error
+}
+
+// Autentica por JWT (Authorization: Bearer <jwt>) ou por chave de API
+// (Authorization: Bearer fk_... ou X-API-Key: fk_...). Em ambos os casos injeta user_id no contexto.
+func AuthMiddleware(apiKeys APIKeyAuthenticator, sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Pega o header Authorization
 		authHeader := c.GetHeader("Authorization")
+		apiKey := c.GetHeader("X-API-Key")
+
+		if apiKey == "" && strings.HasPrefix(authHeader, "Bearer "+apiKeyPrefix) {
+			apiKey = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+		if apiKey != "" {
+			authenticateAPIKey(c, apiKeys, apiKey)
+			return
+		}
+
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token não fornecido"})
 			return
@@ -45,8 +70,46 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Rejeita tokens de sessões encerradas (logout, revogação do dispositivo, redefinição de senha)
+		if sidFloat, ok := claims["sid"].(float64); ok {
+			if err := sessions.ValidateSession(uint(sidFloat), uint(userIDFloat)); err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Sessão encerrada"})
+				return
+			}
+			c.Set("session_id", uint(sidFloat))
+		}
+
 		// Injeta user_id no contexto
 		c.Set("user_id", uint(userIDFloat))
 		c.Next()
 	}
 }
+
+// Valida a chave de API e aplica o escopo: chaves "read" só podem fazer requisições de leitura
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, key string) {
+	userID, scope, err := apiKeys.Authenticate(key)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Chave de API inválida"})
+		return
+	}
+
+	if scope != "write" && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Chave de API sem permissão de escrita"})
+		return
+	}
+
+	c.Set("user_id", userID)
+	c.Set("api_key_scope", scope)
+	c.Next()
+}
+
+// Bloqueia rotas sensíveis (gerenciar chaves, senha, 2FA, conta) para requisições autenticadas por chave de API
+func RequireInteractiveAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("api_key_scope"); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Rota não disponível para chaves de API"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Lê o workspace selecionado no header X-Workspace-ID e injeta workspace_id no contexto.
+// Sem o header, os serviços usam o workspace pessoal do usuário.
+func Workspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("X-Workspace-ID")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		workspaceID, err := strconv.ParseUint(header, 10, 64)
+		if err != nil || workspaceID == 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "X-Workspace-ID inválido"})
+			return
+		}
+
+		c.Set("workspace_id", uint(workspaceID))
+		c.Next()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/daviolvr/Fintrack/api/handlers"
 	"github.com/daviolvr/Fintrack/api/middlewares"
 	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/mailer"
 	"github.com/daviolvr/Fintrack/internal/services"
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
@@ -11,46 +12,166 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(r *gin.Engine, db *gorm.DB, cache *cache.Cache) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, cache *cache.Cache, mailer mailer.Mailer) {
 	// Inicializa serviços
-	authService := services.NewAuthService(db)
+	authService := services.NewAuthService(db, cache, mailer)
+	twoFactorService := services.NewTwoFactorService(db, cache)
+	apiKeyService := services.NewAPIKeyService(db)
+	sessionService := services.NewSessionService(db)
 	userService := services.NewUserService(db, cache)
+	accountService := services.NewAccountService(db, cache)
 	categoryService := services.NewCategoryService(db, cache)
 	transactionService := services.NewTransactionService(db, cache)
+	transferService := services.NewTransferService(db, cache)
+	recurringService := services.NewRecurringService(db, transactionService)
+	budgetService := services.NewBudgetService(db)
+	reportService := services.NewReportService(db, cache)
+	importService := services.NewImportService(db, cache)
+	workspaceService := services.NewWorkspaceService(db, mailer)
+	splitService := services.NewSplitService(db)
+	tagService := services.NewTagService(db, cache)
 
 	// Inicializa handlers
 	authHandler := handlers.NewAuthHandler(authService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
 	userHandler := handlers.NewUserHandler(userService)
+	accountHandler := handlers.NewAccountHandler(accountService)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	transferHandler := handlers.NewTransferHandler(transferService)
+	recurringHandler := handlers.NewRecurringHandler(recurringService)
+	budgetHandler := handlers.NewBudgetHandler(budgetService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	importHandler := handlers.NewImportHandler(importService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
+	splitHandler := handlers.NewSplitHandler(splitService)
+	tagHandler := handlers.NewTagHandler(tagService)
 
-	v1 := r.Group("/api/v1", middlewares.AuthMiddleware())
+	v1 := r.Group("/api/v1", middlewares.AuthMiddleware(apiKeyService, sessionService), middlewares.Workspace())
+
+	// Rotas que exigem login (não aceitam chave de API)
+	interactive := v1.Group("", middlewares.RequireInteractiveAuth())
 
 	// Rotas públicas (sem Auth)
 	r.POST("/api/v1/register", authHandler.Register)
 	r.POST("/api/v1/login", authHandler.Login)
+	r.POST("/api/v1/login/2fa", authHandler.LoginTwoFactor)
 	r.POST("/api/v1/refresh", authHandler.RefreshToken)
+	r.POST("/api/v1/logout", authHandler.Logout)
+	r.POST("/api/v1/password/forgot", authHandler.ForgotPassword)
+	r.POST("/api/v1/password/reset", authHandler.ResetPassword)
+	r.POST("/api/v1/email/verify", authHandler.VerifyEmail)
+	r.POST("/api/v1/email/resend", authHandler.ResendVerification)
+
+	// Rotas de auth (autenticadas)
+	interactive.POST("/logout/all", authHandler.LogoutAll)
 
 	// Rotas de user
 	v1.GET("/users/me", userHandler.Me)
-	v1.PUT("/users/me", userHandler.Update)
-	v1.PATCH("/users/me/balance", userHandler.UpdateBalance)
-	v1.DELETE("/users/me", userHandler.Delete)
-	v1.PUT("/users/password", userHandler.UpdatePassword)
+	interactive.PUT("/users/me", userHandler.Update)
+	interactive.DELETE("/users/me", userHandler.Delete)
+	interactive.PUT("/users/password", userHandler.UpdatePassword)
+	interactive.GET("/users/me/login-history", authHandler.LoginHistory)
+
+	// Rotas de sessions
+	interactive.GET("/users/me/sessions", sessionHandler.List)
+	interactive.DELETE("/users/me/sessions/:id", sessionHandler.Revoke)
+
+	// Rotas de 2FA
+	interactive.POST("/users/me/2fa/setup", twoFactorHandler.Setup)
+	interactive.POST("/users/me/2fa/confirm", twoFactorHandler.Confirm)
+	interactive.POST("/users/me/2fa/disable", twoFactorHandler.Disable)
+	interactive.POST("/users/me/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+
+	// Rotas de API keys
+	interactive.POST("/api-keys", apiKeyHandler.Create)
+	interactive.GET("/api-keys", apiKeyHandler.List)
+	interactive.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
+
+	// Rotas de workspaces
+	interactive.POST("/workspaces", workspaceHandler.Create)
+	v1.GET("/workspaces", workspaceHandler.List)
+	interactive.DELETE("/workspaces/:id", workspaceHandler.Delete)
+	v1.GET("/workspaces/:id/members", workspaceHandler.ListMembers)
+	interactive.PUT("/workspaces/:id/members/:user_id", workspaceHandler.UpdateMemberRole)
+	interactive.DELETE("/workspaces/:id/members/:user_id", workspaceHandler.RemoveMember)
+	interactive.POST("/workspaces/:id/invitations", workspaceHandler.Invite)
+	interactive.POST("/workspaces/invitations/accept", workspaceHandler.AcceptInvitation)
+
+	// Rotas de accounts
+	v1.POST("/accounts", accountHandler.Create)
+	v1.GET("/accounts", accountHandler.List)
+	v1.GET("/accounts/:id", accountHandler.Retrieve)
+	v1.PUT("/accounts/:id", accountHandler.Update)
+	v1.PATCH("/accounts/:id/balance", accountHandler.UpdateBalance)
+	v1.DELETE("/accounts/:id", accountHandler.Delete)
 
 	// Rotas de categories
 	v1.POST("/categories", categoryHandler.Create)
 	v1.GET("/categories", categoryHandler.List)
+	v1.GET("/categories/tree", categoryHandler.Tree)
+	v1.GET("/categories/templates", categoryHandler.ListTemplates)
+	v1.POST("/categories/templates/apply", categoryHandler.ApplyTemplates)
 	v1.PUT("/categories/:id", categoryHandler.Update)
 	v1.DELETE("/categories/:id", categoryHandler.Delete)
+	v1.POST("/categories/:id/merge", categoryHandler.Merge)
+	v1.POST("/categories/:id/archive", categoryHandler.Archive)
+	v1.POST("/categories/:id/unarchive", categoryHandler.Unarchive)
 
 	// Rotas de transactions
 	v1.POST("/transactions", transactionHandler.Create)
 	v1.GET("/transactions", transactionHandler.List)
+	v1.GET("/transactions/export", transactionHandler.Export)
+	v1.POST("/transactions/bulk", transactionHandler.Bulk)
 	v1.GET("/transactions/:id", transactionHandler.Retrieve)
 	v1.PUT("/transactions/:id", transactionHandler.Update)
 	v1.DELETE("/transactions/:id", transactionHandler.Delete)
 
+	// Rotas de tags
+	v1.POST("/tags", tagHandler.Create)
+	v1.GET("/tags", tagHandler.List)
+	v1.PUT("/tags/:id", tagHandler.Update)
+	v1.DELETE("/tags/:id", tagHandler.Delete)
+
+	// Rotas de divisão de despesas
+	v1.POST("/participants", splitHandler.CreateParticipant)
+	v1.GET("/participants", splitHandler.ListParticipants)
+	v1.DELETE("/participants/:id", splitHandler.DeleteParticipant)
+	v1.POST("/settlements", splitHandler.CreateSettlement)
+	v1.GET("/settlements", splitHandler.ListSettlements)
+	v1.DELETE("/settlements/:id", splitHandler.DeleteSettlement)
+	v1.GET("/splits/ledger", splitHandler.Ledger)
+	v1.GET("/splits/simplify", splitHandler.Simplify)
+
+	// Rotas de transfers
+	v1.POST("/transfers", transferHandler.Create)
+	v1.GET("/transfers", transferHandler.List)
+	v1.DELETE("/transfers/:id", transferHandler.Delete)
+
+	// Rotas de recurring transactions
+	v1.POST("/recurring-transactions", recurringHandler.Create)
+	v1.GET("/recurring-transactions", recurringHandler.List)
+	v1.GET("/recurring-transactions/:id", recurringHandler.Retrieve)
+	v1.PUT("/recurring-transactions/:id", recurringHandler.Update)
+	v1.DELETE("/recurring-transactions/:id", recurringHandler.Delete)
+
+	// Rotas de budgets
+	v1.POST("/budgets", budgetHandler.Create)
+	v1.GET("/budgets", budgetHandler.List)
+	v1.GET("/budgets/status", budgetHandler.Status)
+	v1.PUT("/budgets/:id", budgetHandler.Update)
+	v1.DELETE("/budgets/:id", budgetHandler.Delete)
+
+	// Rotas de reports
+	v1.GET("/reports/summary", reportHandler.Summary)
+	v1.GET("/reports/tags", reportHandler.Tags)
+
+	// Rotas de imports
+	v1.POST("/imports/csv", importHandler.ImportCSV)
+	v1.POST("/imports/ofx", importHandler.ImportOFX)
+
 	// Inicializa Swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 }
@@ -3,12 +3,17 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/daviolvr/Fintrack/api/middlewares"
 	"github.com/daviolvr/Fintrack/api/router"
 	"github.com/daviolvr/Fintrack/docs"
 	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/mailer"
 	"github.com/daviolvr/Fintrack/internal/repository"
+	"github.com/daviolvr/Fintrack/internal/scheduler"
+	"github.com/daviolvr/Fintrack/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
@@ -35,14 +40,29 @@ func main() {
 	// Utiliza o engine do Gin
 	r := gin.Default()
 
+	// Só confia no X-Forwarded-For vindo dos proxies listados em TRUSTED_PROXIES
+	// (separados por vírgula). Sem a variável, o IP do cliente é sempre o da conexão.
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatalf("Erro ao configurar proxies confiáveis: %v", err)
+	}
+
 	// Middleware CORS global
 	r.Use(middlewares.CORS())
 
 	// Inicializa cache
 	cache := cache.NewCache()
 
+	// Inicializa o envio de emails
+	mailer := mailer.NewMailer()
+
 	// Seta as rotas
-	router.SetupRoutes(r, db, cache)
+	router.SetupRoutes(r, db, cache, mailer)
+
+	// Inicia o agendador de transações recorrentes (roda a cada hora)
+	recurringService := services.NewRecurringService(db, services.NewTransactionService(db, cache))
+	recurringScheduler := scheduler.New(recurringService, time.Hour)
+	recurringScheduler.Start()
+	defer recurringScheduler.Stop()
 
 	// Configuraçẽos do Swagger
 	docs.SwaggerInfo.Title = "Fintrack API"
@@ -60,3 +80,14 @@ func main() {
 	// Inicializa o servidor
 	r.Run(":" + port)
 }
+
+// Lê a lista de proxies confiáveis (IPs ou CIDRs) do .env
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
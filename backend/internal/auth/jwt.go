@@ -1,16 +1,25 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateJWT(userID uint) (string, error) {
+// Validade do refresh token
+const RefreshTokenTTL = time.Hour * 24 * 3
+
+// Gera o access token vinculado à sessão (sid), que o AuthMiddleware confere a cada requisição
+func GenerateJWT(userID, sessionID uint) (string, error) {
 	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
 	claims := jwt.MapClaims{
 		"user_id": userID,
+		"sid":     sessionID,
 		"exp":     time.Now().Add(time.Hour * 24).Unix(),
 	}
 
@@ -18,12 +27,90 @@ func GenerateJWT(userID uint) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
-func GenerateRefreshToken(userID uint) (string, error) {
+// Gera o refresh token com o identificador único (jti) persistido no banco
+func GenerateRefreshToken(userID uint, jti string, expiresAt time.Time) (string, error) {
 	jwtSecret := []byte(os.Getenv("JWT_REFRESH_SECRET"))
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24 * 3).Unix(),
+		"jti":     jti,
+		"exp":     expiresAt.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+// Valida assinatura e expiração do refresh token e retorna user_id e jti
+func ParseRefreshToken(refreshToken string) (uint, string, error) {
+	jwtRefreshSecret := []byte(os.Getenv("JWT_REFRESH_SECRET"))
+
+	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (any, error) {
+		return jwtRefreshSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return 0, "", errors.New("refresh token inválido")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, "", errors.New("refresh token inválido")
+	}
+
+	userIDFloat, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, "", errors.New("refresh token malformado")
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return 0, "", errors.New("refresh token malformado")
+	}
+
+	return uint(userIDFloat), jti, nil
+}
+
+// Gera um identificador aleatório (jti, família de tokens)
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Gera o token de desafio do login em duas etapas (válido por 5 minutos).
+// Não carrega "user_id", então não é aceito pelo AuthMiddleware como token de acesso.
+func GenerateLoginChallenge(userID uint) (string, error) {
+	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
+	claims := jwt.MapClaims{
+		"sub":     strconv.FormatUint(uint64(userID), 10),
+		"purpose": "2fa",
+		"exp":     time.Now().Add(time.Minute * 5).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
 }
+
+// Valida o token de desafio e retorna o ID do usuário
+func ParseLoginChallenge(challengeToken string) (uint, error) {
+	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
+
+	token, err := jwt.Parse(challengeToken, func(token *jwt.Token) (any, error) {
+		return jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return 0, errors.New("desafio de login inválido ou expirado")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != "2fa" {
+		return 0, errors.New("desafio de login inválido ou expirado")
+	}
+
+	sub, _ := claims["sub"].(string)
+	userID, err := strconv.ParseUint(sub, 10, 64)
+	if err != nil {
+		return 0, errors.New("desafio de login inválido ou expirado")
+	}
+
+	return uint(userID), nil
+}
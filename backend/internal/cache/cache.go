@@ -63,12 +63,25 @@ func (c *Cache) DeleteByPrefix(prefix string) error {
 	return iter.Err()
 }
 
-func (c *Cache) InvalidateUserCategories(userID uint) error {
-	return c.DeleteByPrefix(fmt.Sprintf("categories:%d:*", userID))
+// Os dados financeiros são compartilhados entre os membros, por isso as chaves usam o workspace
+func (c *Cache) InvalidateWorkspaceAccounts(workspaceID uint) error {
+	return c.DeleteByPrefix(fmt.Sprintf("accounts:%d:*", workspaceID))
 }
 
-func (c *Cache) InvalidateUserTransactions(userID uint) error {
-	return c.DeleteByPrefix(fmt.Sprintf("transactions:%d:*", userID))
+func (c *Cache) InvalidateWorkspaceCategories(workspaceID uint) error {
+	return c.DeleteByPrefix(fmt.Sprintf("categories:%d:*", workspaceID))
+}
+
+// Invalida transações e os relatórios derivados delas
+func (c *Cache) InvalidateWorkspaceTransactions(workspaceID uint) error {
+	if err := c.DeleteByPrefix(fmt.Sprintf("transactions:workspace=%d:*", workspaceID)); err != nil {
+		return err
+	}
+	return c.InvalidateWorkspaceReports(workspaceID)
+}
+
+func (c *Cache) InvalidateWorkspaceReports(workspaceID uint) error {
+	return c.DeleteByPrefix(fmt.Sprintf("reports:%d:*", workspaceID))
 }
 
 func (c *Cache) InvalidateUserData(userID uint) error {
@@ -4,6 +4,10 @@ import (
 	"github.com/daviolvr/Fintrack/internal/models"
 )
 
+type AccountCacheData struct {
+	Accounts []models.Account
+}
+
 type CategoryCacheData struct {
 	Categories []models.Category
 	Total      int
@@ -16,4 +20,6 @@ type TransactionRetrieveCacheData struct {
 type TransactionListCacheData struct {
 	Transactions []models.Transaction
 	Total        int
+	NextCursor   string
+	PrevCursor   string
 }
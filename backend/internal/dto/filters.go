@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/money"
+)
+
+// Filtros opcionais aceitos na listagem de transações (e reaproveitados em relatórios)
+type TransactionFilter struct {
+	FromDate   *time.Time
+	ToDate     *time.Time
+	CategoryID *uint
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	Type       *string
+	Tags       []string // Nomes das etiquetas, em minúsculas
+	TagMode    string   // any (padrão): qualquer etiqueta; all: todas
+	Query      string   // Busca textual na descrição
+}
+
+// Ordenação da listagem de transações
+type TransactionSort struct {
+	Field string // date, amount ou created_at; vazio: date (ou relevância, na busca textual)
+	Order string // asc ou desc (padrão)
+}
+
+// Conteúdo do cursor opaco da paginação por cursor: a ordenação usada e a
+// posição (valor da coluna ordenada + ID) da transação na borda da página
+type TransactionCursor struct {
+	Field    string `json:"f"`
+	Order    string `json:"o"`
+	Backward bool   `json:"b,omitempty"` // true: busca a página anterior
+	Value    string `json:"v"`
+	ID       uint   `json:"i"`
+}
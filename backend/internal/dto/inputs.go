@@ -1,10 +1,13 @@
 package dto
 
+import "github.com/daviolvr/Fintrack/internal/money"
+
 type RegisterInput struct {
 	FirstName string `json:"first_name" binding:"required,min=2,max=50"`
 	LastName  string `json:"last_name" binding:"required,min=2,max=50"`
 	Email     string `json:"email" binding:"required,email"`
 	Password  string `json:"password" binding:"required,min=6"`
+	Locale    string `json:"locale" binding:"omitempty,oneof=pt-BR en-US"` // Idioma das categorias iniciais (padrão: pt-BR)
 }
 
 type LoginInput struct {
@@ -16,16 +19,176 @@ type RefreshTokenInput struct {
 	RefreshToken string `json:"refresh_token" binding:"required,jwt"`
 }
 
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required,max=128"`
+	NewPassword string `json:"new_password" binding:"required,min=6,max=72"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required,max=128"`
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required,jwt"`
+	Code           string `json:"code" binding:"required,max=20"`
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required,max=20"`
+}
+
+type TwoFactorDisableInput struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,max=20"`
+}
+
 type CategoryInput struct {
+	Name     string `json:"name" binding:"required,min=2,max=50"`
+	Type     string `json:"type" binding:"omitempty,oneof=income expense both"` // Padrão: both
+	ParentID *uint  `json:"parent_id" binding:"omitempty,min=1"`                // Categoria pai (opcional)
+	Color    string `json:"color" binding:"omitempty,hexcolor,len=7"`           // #RRGGBB
+	Icon     string `json:"icon" binding:"max=50"`
+}
+
+// Aplica um conjunto de categorias modelo no workspace
+type CategoryTemplateInput struct {
+	Locale string   `json:"locale" binding:"required,oneof=pt-BR en-US"`
+	Names  []string `json:"names" binding:"omitempty,max=50,dive,min=1,max=50"` // Vazio aplica todas do idioma
+}
+
+type CategoryMergeInput struct {
+	TargetID uint `json:"target_id" binding:"required,min=1"`
+}
+
+type AccountInput struct {
 	Name string `json:"name" binding:"required,min=2,max=50"`
+	Type string `json:"type" binding:"required,oneof=checking savings cash credit_card"`
+}
+
+type AccountCreateInput struct {
+	Name    string       `json:"name" binding:"required,min=2,max=50"`
+	Type    string       `json:"type" binding:"required,oneof=checking savings cash credit_card"`
+	Balance money.Amount `json:"balance"`
+}
+
+type AccountUpdateBalanceInput struct {
+	Balance money.Amount `json:"balance" binding:"required"`
 }
 
 type TransactionInput struct {
-	CategoryID  uint    `json:"category_id" binding:"required,min=1"`
-	Type        string  `json:"type" binding:"required,oneof=income expense"`
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Description string  `json:"description" binding:"max=255"`
-	Date        string  `json:"date" binding:"required,datetime=2006-01-02"`
+	AccountID   uint         `json:"account_id" binding:"required,min=1"`
+	CategoryID  uint         `json:"category_id" binding:"required,min=1"`
+	Type        string       `json:"type" binding:"required,oneof=income expense"`
+	Amount      money.Amount `json:"amount" binding:"required,gt=0"`
+	Description string       `json:"description" binding:"max=255"`
+	Date        string       `json:"date" binding:"required,datetime=2006-01-02"`
+	RecurringID *uint        `json:"-"`                                                 // Preenchido apenas pelo agendador de recorrências
+	Split       *SplitInput  `json:"split"`                                             // Divide a despesa entre participantes (opcional)
+	Tags        []string     `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"` // Etiquetas inexistentes são criadas
+}
+
+// Divisão de uma despesa entre participantes
+type SplitInput struct {
+	Type         string            `json:"type" binding:"required,oneof=equal exact percent shares"`
+	Participants []SplitEntryInput `json:"participants" binding:"required,min=1,max=50,dive"`
+}
+
+type SplitEntryInput struct {
+	ParticipantID uint `json:"participant_id"` // 0 representa quem pagou
+	// Valor da parte na divisão exata (exact)
+	Amount *money.Amount `json:"amount" binding:"omitempty,gte=0" swaggertype:"number"`
+	// Percentual (percent) ou peso (shares). Ignorado nas divisões igual (equal) e exata (exact).
+	Value float64 `json:"value" binding:"gte=0,lte=10000"`
+}
+
+// Operações em lote sobre transações, executadas nesta ordem: create, update e delete
+type TransactionBulkInput struct {
+	Create  []TransactionInput       `json:"create" binding:"omitempty,max=500,dive"`
+	Update  []TransactionBulkUpdate  `json:"update" binding:"omitempty,max=50,dive"`
+	Delete  *TransactionBulkSelector `json:"delete"`
+	Partial bool                     `json:"-"` // Query param partial=true: aplica os itens válidos mesmo que outros falhem
+}
+
+// Seleciona as transações por lista de IDs e/ou pelos mesmos filtros da listagem
+type TransactionBulkSelector struct {
+	IDs    []uint                 `json:"ids" binding:"omitempty,max=1000"`
+	Filter *TransactionBulkFilter `json:"filter"`
+}
+
+type TransactionBulkFilter struct {
+	FromDate   string        `json:"from_date" binding:"omitempty,datetime=2006-01-02"`
+	ToDate     string        `json:"to_date" binding:"omitempty,datetime=2006-01-02"`
+	CategoryID *uint         `json:"category_id"`
+	MinAmount  *money.Amount `json:"min_amount" swaggertype:"number"`
+	MaxAmount  *money.Amount `json:"max_amount" swaggertype:"number"`
+	Type       *string       `json:"type" binding:"omitempty,oneof=income expense"`
+	Tags       []string      `json:"tags"`
+	TagMode    string        `json:"tag_mode" binding:"omitempty,oneof=any all"`
+	Query      string        `json:"q" binding:"max=200"`
+}
+
+// Altera os campos informados em todas as transações selecionadas
+type TransactionBulkUpdate struct {
+	TransactionBulkSelector
+	AccountID   *uint     `json:"account_id" binding:"omitempty,min=1"`
+	CategoryID  *uint     `json:"category_id" binding:"omitempty,min=1"`
+	Description *string   `json:"description" binding:"omitempty,max=255"`
+	Date        *string   `json:"date" binding:"omitempty,datetime=2006-01-02"`
+	Tags        *[]string `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`     // Substitui as etiquetas
+	AddTags     []string  `json:"add_tags" binding:"omitempty,max=20,dive,min=1,max=50"` // Acrescenta etiquetas
+	RemoveTags  []string  `json:"remove_tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+type TagInput struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+type ParticipantInput struct {
+	Name   string `json:"name" binding:"required,min=1,max=100"`
+	UserID *uint  `json:"user_id" binding:"omitempty,min=1"` // Vincula a um membro do workspace
+}
+
+type SettlementInput struct {
+	FromParticipantID uint         `json:"from_participant_id" binding:"required,min=1"`
+	ToParticipantID   uint         `json:"to_participant_id" binding:"required,min=1,nefield=FromParticipantID"`
+	Amount            money.Amount `json:"amount" binding:"required,gt=0"`
+	Date              string       `json:"date" binding:"required,datetime=2006-01-02"`
+	Note              string       `json:"note" binding:"max=255"`
+}
+
+type RecurringTransactionInput struct {
+	AccountID   uint         `json:"account_id" binding:"required,min=1"`
+	CategoryID  uint         `json:"category_id" binding:"required,min=1"`
+	Type        string       `json:"type" binding:"required,oneof=income expense"`
+	Amount      money.Amount `json:"amount" binding:"required,gt=0"`
+	Description string       `json:"description" binding:"max=255"`
+	Frequency   string       `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	Interval    int          `json:"interval" binding:"omitempty,min=1,max=365"`
+	DayOfMonth  *int         `json:"day_of_month" binding:"omitempty,min=1,max=31"`
+	StartDate   string       `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate     string       `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	Count       *int         `json:"count" binding:"omitempty,min=1"`
+	Active      *bool        `json:"active"`
+}
+
+type TransferInput struct {
+	FromAccountID uint         `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   uint         `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        money.Amount `json:"amount" binding:"required,gt=0"`
+	Description   string       `json:"description" binding:"max=255"`
+	Date          string       `json:"date" binding:"required,datetime=2006-01-02"`
+}
+
+type BudgetInput struct {
+	CategoryID uint         `json:"category_id" binding:"required,min=1"`
+	Limit      money.Amount `json:"limit" binding:"required,gt=0"`
+	StartMonth string       `json:"start_month" binding:"required,datetime=2006-01"`
+	EndMonth   string       `json:"end_month" binding:"omitempty,datetime=2006-01"`
+	Rollover   bool         `json:"rollover"`
 }
 
 type UserUpdateInput struct {
@@ -34,10 +197,6 @@ type UserUpdateInput struct {
 	Email     string `json:"email" binding:"omitempty,email"`
 }
 
-type UserUpdateBalanceInput struct {
-	Balance float64 `json:"balance" binding:"required"`
-}
-
 type UserDeleteInput struct {
 	Password string `json:"password" binding:"required"`
 }
@@ -46,3 +205,46 @@ type UserUpdatePasswordInput struct {
 	Password    string `json:"password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=6,max=72,nefield=Password"`
 }
+
+type CSVImportInput struct {
+	AccountID         uint   `form:"account_id" binding:"required,min=1"`
+	Commit            bool   `form:"commit"`
+	Delimiter         string `form:"delimiter" binding:"omitempty,len=1"`
+	HasHeader         *bool  `form:"has_header"`
+	DateColumn        string `form:"date_column" binding:"required"`
+	DateFormat        string `form:"date_format" binding:"max=20"`
+	AmountColumn      string `form:"amount_column" binding:"required"`
+	AmountSign        string `form:"amount_sign" binding:"omitempty,oneof=negative_expense positive_expense"`
+	DecimalSeparator  string `form:"decimal_separator" binding:"omitempty,len=1"`
+	DescriptionColumn string `form:"description_column"`
+	CategoryColumn    string `form:"category_column"`
+	DefaultCategoryID uint   `form:"default_category_id"`
+}
+
+type OFXImportInput struct {
+	AccountID         uint `form:"account_id" binding:"required,min=1"`
+	DefaultCategoryID uint `form:"default_category_id" binding:"required,min=1"`
+}
+
+type APIKeyInput struct {
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	Scope     string `json:"scope" binding:"omitempty,oneof=read write"`
+	ExpiresAt string `json:"expires_at" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type WorkspaceInput struct {
+	Name string `json:"name" binding:"required,min=2,max=100"`
+}
+
+type WorkspaceInvitationInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type WorkspaceMemberRoleInput struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type AcceptInvitationInput struct {
+	Token string `json:"token" binding:"required,max=128"`
+}
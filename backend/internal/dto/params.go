@@ -11,20 +11,66 @@ type UserChangePasswordParam struct {
 	NewPassword string `json:"new_password"`
 }
 
+type AccountCreateParam struct {
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	Balance float64 `json:"balance"`
+}
+
+type AccountUpdateParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
 type TransactionCreateParam struct {
-	CategoryID  int64   `json:"category_id"`
-	Type        string  `json:"type"`
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
-	Date        string  `json:"date"`
+	AccountID   int64       `json:"account_id"`
+	CategoryID  int64       `json:"category_id"`
+	Type        string      `json:"type"`
+	Amount      float64     `json:"amount"`
+	Description string      `json:"description"`
+	Date        string      `json:"date"`
+	Split       *SplitInput `json:"split"`
 }
 
 type TransactionUpdateParam struct {
+	AccountID   int64       `json:"account_id"`
+	CategoryID  int64       `json:"category_id"`
+	Type        string      `json:"type"`
+	Amount      float64     `json:"amount"`
+	Description string      `json:"description"`
+	Date        string      `json:"date"`
+	Split       *SplitInput `json:"split"`
+}
+
+type TransferCreateParam struct {
+	FromAccountID int64   `json:"from_account_id"`
+	ToAccountID   int64   `json:"to_account_id"`
+	Amount        float64 `json:"amount"`
+	Description   string  `json:"description"`
+	Date          string  `json:"date"`
+}
+
+type RecurringTransactionParam struct {
+	AccountID   int64   `json:"account_id"`
 	CategoryID  int64   `json:"category_id"`
 	Type        string  `json:"type"`
 	Amount      float64 `json:"amount"`
 	Description string  `json:"description"`
-	Date        string  `json:"date"`
+	Frequency   string  `json:"frequency"`
+	Interval    int     `json:"interval"`
+	DayOfMonth  int     `json:"day_of_month"`
+	StartDate   string  `json:"start_date"`
+	EndDate     string  `json:"end_date"`
+	Count       int     `json:"count"`
+	Active      bool    `json:"active"`
+}
+
+type BudgetParam struct {
+	CategoryID int64   `json:"category_id"`
+	Limit      float64 `json:"limit"`
+	StartMonth string  `json:"start_month"`
+	EndMonth   string  `json:"end_month"`
+	Rollover   bool    `json:"rollover"`
 }
 
 type BalanceUpdateParam struct {
@@ -2,6 +2,8 @@ package dto
 
 import (
 	"time"
+
+	"github.com/daviolvr/Fintrack/internal/money"
 )
 
 type MessageResponse struct {
@@ -13,11 +15,12 @@ type ErrorResponse struct {
 }
 
 type UserMeResponse struct {
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Email     string    `json:"email"`
-	Balance   float64   `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
+	FirstName string       `json:"first_name"`
+	LastName  string       `json:"last_name"`
+	Email     string       `json:"email"`
+	Balance   money.Amount `json:"balance" swaggertype:"number"`
+	TwoFactor bool         `json:"two_factor_enabled"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 type UserUpdateResponse struct {
@@ -27,13 +30,59 @@ type UserUpdateResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-type UserUpdateBalanceResponse struct {
-	Balance float64 `json:"balance"`
+type AccountResponse struct {
+	ID        uint         `json:"id"`
+	Name      string       `json:"name"`
+	Type      string       `json:"type"`
+	Balance   money.Amount `json:"balance" swaggertype:"number"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type AccountListResponse struct {
+	Data         []AccountResponse `json:"data"`
+	TotalBalance money.Amount      `json:"total_balance" swaggertype:"number"`
+}
+
+type AccountUpdateBalanceResponse struct {
+	Balance money.Amount `json:"balance" swaggertype:"number"`
 }
 
 type CategoryResponse struct {
-	ID   uint   `json:"id"`
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	ParentID *uint  `json:"parent_id"`
+	Color    string `json:"color"`
+	Icon     string `json:"icon"`
+	Archived bool   `json:"archived"`
+}
+
+// Categoria com suas subcategorias aninhadas
+type CategoryTreeResponse struct {
+	ID       uint                   `json:"id"`
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	Color    string                 `json:"color"`
+	Icon     string                 `json:"icon"`
+	Archived bool                   `json:"archived"`
+	Children []CategoryTreeResponse `json:"children"`
+}
+
+type CategoryTemplateItem struct {
 	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Categorias modelo disponíveis em um idioma
+type CategoryTemplateResponse struct {
+	Locale     string                 `json:"locale"`
+	Categories []CategoryTemplateItem `json:"categories"`
+}
+
+// Resultado da aplicação de categorias modelo
+type ApplyCategoryTemplatesResponse struct {
+	Created []CategoryResponse `json:"created"`
+	Skipped []string           `json:"skipped"` // Já existiam no workspace
 }
 
 type PaginatedCategoriesResponse struct {
@@ -45,21 +94,111 @@ type PaginatedCategoriesResponse struct {
 }
 
 type TransactionCreateResponse struct {
-	CategoryID  uint      `json:"category_id"`
-	Type        string    `json:"type"` // "income" ou "expense"
-	Amount      float64   `json:"amount" db:"amount"`
-	Description string    `json:"description"`
-	Date        time.Time `json:"date"`
+	ID          uint                       `json:"id"`
+	AccountID   uint                       `json:"account_id"`
+	CategoryID  uint                       `json:"category_id"`
+	Type        string                     `json:"type"` // "income" ou "expense"
+	Amount      money.Amount               `json:"amount" db:"amount" swaggertype:"number"`
+	Description string                     `json:"description"`
+	Date        time.Time                  `json:"date"`
+	SplitType   *string                    `json:"split_type,omitempty"`
+	ShareAmount *money.Amount              `json:"share_amount,omitempty" swaggertype:"number"` // Parte de quem pagou
+	Splits      []TransactionSplitResponse `json:"splits,omitempty"`
+	Tags        []TagResponse              `json:"tags"`
 }
 
 type TransactionResponse struct {
-	CategoryID  uint      `json:"category_id"`
-	Type        string    `json:"type"` // "income" ou "expense"
-	Amount      float64   `json:"amount" db:"amount"`
-	Description string    `json:"description"`
-	Date        time.Time `json:"date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint                       `json:"id"`
+	AccountID   uint                       `json:"account_id"`
+	CategoryID  uint                       `json:"category_id"`
+	Type        string                     `json:"type"` // "income" ou "expense"
+	Amount      money.Amount               `json:"amount" db:"amount" swaggertype:"number"`
+	Description string                     `json:"description"`
+	Date        time.Time                  `json:"date"`
+	SplitType   *string                    `json:"split_type,omitempty"`
+	ShareAmount *money.Amount              `json:"share_amount,omitempty" swaggertype:"number"` // Parte de quem pagou
+	Splits      []TransactionSplitResponse `json:"splits,omitempty"`
+	Tags        []TagResponse              `json:"tags"`
+	Highlight   string                     `json:"highlight,omitempty"` // Presente na busca por q: termos marcados com <mark>
+	CreatedAt   time.Time                  `json:"created_at"`
+	UpdatedAt   time.Time                  `json:"updated_at"`
+}
+
+type TransactionSplitResponse struct {
+	ParticipantID uint         `json:"participant_id"`
+	Name          string       `json:"name"`
+	Amount        money.Amount `json:"amount" swaggertype:"number"`
+}
+
+type TagResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// Totais das transações de uma etiqueta
+type TagReportItem struct {
+	TagID   uint         `json:"tag_id"`
+	Name    string       `json:"name"`
+	Income  money.Amount `json:"income" swaggertype:"number"`
+	Expense money.Amount `json:"expense" swaggertype:"number"`
+	Net     money.Amount `json:"net" swaggertype:"number"`
+	Count   int          `json:"count"`
+}
+
+// Totais por etiqueta. Uma transação com várias etiquetas entra no total de cada uma.
+type TagReportResponse struct {
+	Tags []TagReportItem `json:"tags"`
+}
+
+type ParticipantResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	UserID    *uint     `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SettlementResponse struct {
+	ID                uint         `json:"id"`
+	FromParticipantID uint         `json:"from_participant_id"`
+	FromName          string       `json:"from_name"`
+	ToParticipantID   uint         `json:"to_participant_id"`
+	ToName            string       `json:"to_name"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	Date              time.Time    `json:"date"`
+	Note              string       `json:"note"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+type PaginatedSettlementResponse struct {
+	Data       []SettlementResponse `json:"data"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"totalPages"`
+}
+
+// "from" deve "amount" a "to"
+type DebtResponse struct {
+	FromParticipantID uint         `json:"from_participant_id"`
+	FromName          string       `json:"from_name"`
+	ToParticipantID   uint         `json:"to_participant_id"`
+	ToName            string       `json:"to_name"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+}
+
+type ParticipantBalanceResponse struct {
+	ParticipantID uint         `json:"participant_id"`
+	Name          string       `json:"name"`
+	Balance       money.Amount `json:"balance" swaggertype:"number"` // Positivo: tem a receber; negativo: deve
+}
+
+type SplitLedgerResponse struct {
+	Balances []ParticipantBalanceResponse `json:"balances"`
+	Debts    []DebtResponse               `json:"debts"`
+}
+
+type SimplifiedDebtsResponse struct {
+	Payments []DebtResponse `json:"payments"` // Menor conjunto de pagamentos que quita todas as dívidas
 }
 
 type PaginatedTransactionResponse struct {
@@ -68,6 +207,234 @@ type PaginatedTransactionResponse struct {
 	Page       int                   `json:"page"`
 	Limit      int                   `json:"limit"`
 	TotalPages int                   `json:"totalPages"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	PrevCursor string                `json:"prev_cursor,omitempty"`
+}
+
+type TransferResponse struct {
+	ID            uint         `json:"id"`
+	FromAccountID uint         `json:"from_account_id"`
+	ToAccountID   uint         `json:"to_account_id"`
+	Amount        money.Amount `json:"amount" swaggertype:"number"`
+	Description   string       `json:"description"`
+	Date          time.Time    `json:"date"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type PaginatedTransferResponse struct {
+	Data       []TransferResponse `json:"data"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"totalPages"`
+}
+
+type RecurringTransactionResponse struct {
+	ID          uint         `json:"id"`
+	AccountID   uint         `json:"account_id"`
+	CategoryID  uint         `json:"category_id"`
+	Type        string       `json:"type"`
+	Amount      money.Amount `json:"amount" swaggertype:"number"`
+	Description string       `json:"description"`
+	Frequency   string       `json:"frequency"`
+	Interval    int          `json:"interval"`
+	DayOfMonth  *int         `json:"day_of_month,omitempty"`
+	StartDate   time.Time    `json:"start_date"`
+	EndDate     *time.Time   `json:"end_date,omitempty"`
+	Count       *int         `json:"count,omitempty"`
+	Occurrences int          `json:"occurrences"`
+	NextRunDate *time.Time   `json:"next_run_date,omitempty"`
+	Active      bool         `json:"active"`
+	LastError   string       `json:"last_error,omitempty"` // Por que a última ocorrência não foi gerada
+	FailedAt    *time.Time   `json:"failed_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type BudgetResponse struct {
+	ID         uint         `json:"id"`
+	CategoryID uint         `json:"category_id"`
+	Limit      money.Amount `json:"limit" swaggertype:"number"`
+	StartMonth string       `json:"start_month"`
+	EndMonth   string       `json:"end_month,omitempty"`
+	Rollover   bool         `json:"rollover"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type BudgetStatusItem struct {
+	BudgetID       uint         `json:"budget_id"`
+	CategoryID     uint         `json:"category_id"`
+	CategoryName   string       `json:"category_name"`
+	Limit          money.Amount `json:"limit" swaggertype:"number"`
+	RolloverAmount money.Amount `json:"rollover_amount" swaggertype:"number"`
+	Available      money.Amount `json:"available" swaggertype:"number"`
+	Spent          money.Amount `json:"spent" swaggertype:"number"`
+	Remaining      money.Amount `json:"remaining" swaggertype:"number"`
+	PercentageUsed float64      `json:"percentage_used"`
+}
+
+type BudgetStatusResponse struct {
+	Month          string             `json:"month"`
+	Data           []BudgetStatusItem `json:"data"`
+	TotalAvailable money.Amount       `json:"total_available" swaggertype:"number"`
+	TotalSpent     money.Amount       `json:"total_spent" swaggertype:"number"`
+	TotalRemaining money.Amount       `json:"total_remaining" swaggertype:"number"`
+}
+
+type ReportBreakdownItem struct {
+	Key     string       `json:"key"`
+	Label   string       `json:"label"`
+	Income  money.Amount `json:"income" swaggertype:"number"`
+	Expense money.Amount `json:"expense" swaggertype:"number"`
+	Count   int          `json:"count"`
+}
+
+type ReportPeriod struct {
+	Period    string                `json:"period"`
+	Income    money.Amount          `json:"income" swaggertype:"number"`
+	Expense   money.Amount          `json:"expense" swaggertype:"number"`
+	Net       money.Amount          `json:"net" swaggertype:"number"`
+	Count     int                   `json:"count"`
+	Breakdown []ReportBreakdownItem `json:"breakdown,omitempty"`
+}
+
+type ReportTotals struct {
+	Income    money.Amount          `json:"income" swaggertype:"number"`
+	Expense   money.Amount          `json:"expense" swaggertype:"number"`
+	Net       money.Amount          `json:"net" swaggertype:"number"`
+	Count     int                   `json:"count"`
+	Breakdown []ReportBreakdownItem `json:"breakdown,omitempty"`
+}
+
+type ReportSummaryResponse struct {
+	GroupBy string         `json:"group_by"`
+	SplitBy string         `json:"split_by,omitempty"`
+	Series  []ReportPeriod `json:"series"`
+	Totals  ReportTotals   `json:"totals"`
+}
+
+// Linha exportada: transação com os nomes da conta e da categoria
+type TransactionExportRow struct {
+	ID           uint         `json:"id"`
+	Date         time.Time    `json:"date"`
+	Type         string       `json:"type"`
+	Amount       money.Amount `json:"amount" swaggertype:"number"`
+	Description  string       `json:"description"`
+	AccountID    uint         `json:"account_id"`
+	AccountName  string       `json:"account_name"`
+	AccountType  string       `json:"-"`
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	FITID        *string      `json:"fitid,omitempty"`
+}
+
+type ImportRowResponse struct {
+	Line        int          `json:"line"`
+	Date        string       `json:"date,omitempty"`
+	Description string       `json:"description"`
+	Type        string       `json:"type,omitempty"`
+	Amount      money.Amount `json:"amount" swaggertype:"number"`
+	CategoryID  uint         `json:"category_id,omitempty"`
+	FITID       string       `json:"fitid,omitempty"`
+	Status      string       `json:"status,omitempty"` // "created", "skipped" ou "rejected" (OFX)
+	Errors      []string     `json:"errors,omitempty"`
+}
+
+type ImportResponse struct {
+	Committed    bool                `json:"committed"`
+	TotalRows    int                 `json:"total_rows"`
+	ValidRows    int                 `json:"valid_rows"`
+	InvalidRows  int                 `json:"invalid_rows"`
+	TotalIncome  money.Amount        `json:"total_income" swaggertype:"number"`
+	TotalExpense money.Amount        `json:"total_expense" swaggertype:"number"`
+	Net          money.Amount        `json:"net" swaggertype:"number"`
+	Rows         []ImportRowResponse `json:"rows"`
+}
+
+type TransactionBulkResponse struct {
+	Committed bool                        `json:"committed"`
+	Succeeded int                         `json:"succeeded"`
+	Failed    int                         `json:"failed"`
+	Results   []TransactionBulkItemResult `json:"results"`
+}
+
+// Resultado de cada transação criada, alterada ou removida
+type TransactionBulkItemResult struct {
+	Operation string `json:"operation"` // create, update ou delete
+	Index     int    `json:"index"`     // Posição do item em create/update; em delete, posição na lista de transações selecionadas
+	ID        uint   `json:"id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type OFXImportResponse struct {
+	Created  int                 `json:"created"`
+	Skipped  int                 `json:"skipped"`
+	Rejected int                 `json:"rejected"`
+	Rows     []ImportRowResponse `json:"rows"`
+}
+
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type APIKeyCreateResponse struct {
+	APIKeyResponse
+	Key string `json:"key"` // Exibida uma única vez
+}
+
+type WorkspaceResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Personal  bool      `json:"personal"`
+	Role      string    `json:"role"` // Papel do usuário autenticado
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceMemberResponse struct {
+	UserID   uint      `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type WorkspaceInvitationResponse struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type LoginAttemptResponse struct {
+	ID        uint      `json:"id"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PaginatedLoginAttemptsResponse struct {
+	Data       []LoginAttemptResponse `json:"data"`
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalPages int                    `json:"totalPages"`
 }
 
 type LoginResponse struct {
@@ -75,6 +442,21 @@ type LoginResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type LoginChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 type RefreshTokenResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 }
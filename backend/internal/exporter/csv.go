@@ -0,0 +1,61 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+)
+
+type csvEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) ContentType() string  { return "text/csv; charset=utf-8" }
+func (e *csvEncoder) Extension() string    { return "csv" }
+func (e *csvEncoder) GroupByAccount() bool { return false }
+
+func (e *csvEncoder) Write(row *dto.TransactionExportRow) error {
+	if !e.headerWritten {
+		e.headerWritten = true
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(row.ID), 10),
+		row.Date.Format("2006-01-02"),
+		row.Type,
+		row.Amount.String(),
+		row.Description,
+		strconv.FormatUint(uint64(row.AccountID), 10),
+		row.AccountName,
+		strconv.FormatUint(uint64(row.CategoryID), 10),
+		row.CategoryName,
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	// Arquivo sem transações ainda leva o cabeçalho
+	if !e.headerWritten {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) writeHeader() error {
+	return e.w.Write([]string{
+		"id", "date", "type", "amount", "description",
+		"account_id", "account_name", "category_id", "category_name",
+	})
+}
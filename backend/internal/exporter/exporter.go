@@ -0,0 +1,33 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+)
+
+// Escreve as transações exportadas linha a linha, sem manter o histórico em memória
+type Encoder interface {
+	// Tipo MIME e extensão do arquivo gerado
+	ContentType() string
+	Extension() string
+	// Indica se as linhas devem chegar agrupadas por conta
+	GroupByAccount() bool
+	Write(row *dto.TransactionExportRow) error
+	// Finaliza o arquivo (fecha estruturas abertas e descarrega o buffer)
+	Close() error
+}
+
+// Cria o encoder do formato informado ("csv", "json" ou "ofx")
+func New(format string, w io.Writer, filter dto.TransactionFilter) (Encoder, error) {
+	switch format {
+	case "", "csv":
+		return newCSVEncoder(w), nil
+	case "json":
+		return newJSONEncoder(w), nil
+	case "ofx":
+		return newOFXEncoder(w, filter), nil
+	}
+	return nil, fmt.Errorf("formato inválido: %q, use csv, json ou ofx", format)
+}
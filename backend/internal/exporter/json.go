@@ -0,0 +1,49 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+)
+
+// Escreve um array JSON elemento a elemento
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONEncoder(w io.Writer) *jsonEncoder {
+	return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) ContentType() string  { return "application/json; charset=utf-8" }
+func (e *jsonEncoder) Extension() string    { return "json" }
+func (e *jsonEncoder) GroupByAccount() bool { return false }
+
+func (e *jsonEncoder) Write(row *dto.TransactionExportRow) error {
+	prefix := ","
+	if e.count == 0 {
+		prefix = "["
+	}
+	e.count++
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "]"
+	if e.count == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
@@ -0,0 +1,169 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+)
+
+// Gera um OFX 2.x (XML) com um extrato por conta: STMTRS (em BANKMSGSRSV1) para contas
+// bancárias e CCSTMTRS (em CREDITCARDMSGSRSV1) para cartões de crédito. As contas chegam
+// com os cartões por último, então cada conjunto de mensagens é aberto uma única vez.
+type ofxEncoder struct {
+	w          *bufio.Writer
+	filter     dto.TransactionFilter
+	now        time.Time
+	started    bool
+	accountID  uint
+	inAccount  bool
+	creditCard bool // O conjunto de mensagens aberto é o de cartões de crédito
+}
+
+func newOFXEncoder(w io.Writer, filter dto.TransactionFilter) *ofxEncoder {
+	return &ofxEncoder{w: bufio.NewWriter(w), filter: filter, now: time.Now()}
+}
+
+func (e *ofxEncoder) ContentType() string  { return "application/x-ofx" }
+func (e *ofxEncoder) Extension() string    { return "ofx" }
+func (e *ofxEncoder) GroupByAccount() bool { return true }
+
+func (e *ofxEncoder) Write(row *dto.TransactionExportRow) error {
+	if !e.started {
+		e.writeHeader()
+	}
+
+	// Ao mudar de conta, fecha o extrato anterior e abre um novo
+	if !e.inAccount || row.AccountID != e.accountID {
+		e.closeAccount()
+		e.openAccount(row)
+	}
+
+	trnType, amount := "CREDIT", row.Amount
+	if row.Type == "expense" {
+		trnType, amount = "DEBIT", -row.Amount
+	}
+
+	fitid := fmt.Sprintf("fintrack-%d", row.ID)
+	if row.FITID != nil {
+		fitid = *row.FITID
+	}
+
+	fmt.Fprintf(e.w,
+		"<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT>"+
+			"<FITID>%s</FITID><NAME>%s</NAME><MEMO>%s</MEMO></STMTTRN>\n",
+		trnType,
+		row.Date.Format("20060102"),
+		amount.String(),
+		html.EscapeString(fitid),
+		html.EscapeString(truncate(row.Description, 32)),
+		html.EscapeString(row.Description),
+	)
+
+	return nil
+}
+
+func (e *ofxEncoder) Close() error {
+	if !e.started {
+		e.writeHeader()
+	}
+	e.closeAccount()
+	e.w.WriteString(e.messageSetClose() + "\n</OFX>\n")
+	return e.w.Flush()
+}
+
+func (e *ofxEncoder) writeHeader() {
+	e.started = true
+	e.w.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	e.w.WriteString(`<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	fmt.Fprintf(e.w,
+		"<OFX>\n<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>"+
+			"<DTSERVER>%s</DTSERVER><LANGUAGE>POR</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n<BANKMSGSRSV1>\n",
+		e.now.Format("20060102150405"),
+	)
+}
+
+// Abre o extrato da conta. As linhas chegam ordenadas por data, então a primeira define o início.
+func (e *ofxEncoder) openAccount(row *dto.TransactionExportRow) {
+	e.inAccount = true
+	e.accountID = row.AccountID
+
+	// Primeiro cartão: fecha as mensagens bancárias e abre as de cartão de crédito
+	if row.AccountType == "credit_card" && !e.creditCard {
+		e.w.WriteString("</BANKMSGSRSV1>\n<CREDITCARDMSGSRSV1>\n")
+		e.creditCard = true
+	}
+
+	start := row.Date
+	if e.filter.FromDate != nil {
+		start = *e.filter.FromDate
+	}
+	end := e.now
+	if e.filter.ToDate != nil {
+		end = *e.filter.ToDate
+	}
+
+	if e.creditCard {
+		fmt.Fprintf(e.w,
+			"<CCSTMTTRNRS><TRNUID>%d</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n"+
+				"<CCSTMTRS><CURDEF>BRL</CURDEF><CCACCTFROM><ACCTID>%d</ACCTID></CCACCTFROM>\n"+
+				"<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n",
+			row.AccountID,
+			row.AccountID,
+			start.Format("20060102"),
+			end.Format("20060102"),
+		)
+		return
+	}
+
+	fmt.Fprintf(e.w,
+		"<STMTTRNRS><TRNUID>%d</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n"+
+			"<STMTRS><CURDEF>BRL</CURDEF><BANKACCTFROM><BANKID>0000</BANKID><ACCTID>%d</ACCTID>"+
+			"<ACCTTYPE>%s</ACCTTYPE></BANKACCTFROM>\n<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n",
+		row.AccountID,
+		row.AccountID,
+		ofxAccountType(row.AccountType),
+		start.Format("20060102"),
+		end.Format("20060102"),
+	)
+}
+
+func (e *ofxEncoder) closeAccount() {
+	if !e.inAccount {
+		return
+	}
+	e.inAccount = false
+	if e.creditCard {
+		e.w.WriteString("</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS>\n")
+		return
+	}
+	e.w.WriteString("</BANKTRANLIST></STMTRS></STMTTRNRS>\n")
+}
+
+// Fechamento do conjunto de mensagens aberto
+func (e *ofxEncoder) messageSetClose() string {
+	if e.creditCard {
+		return "</CREDITCARDMSGSRSV1>"
+	}
+	return "</BANKMSGSRSV1>"
+}
+
+// Converte o tipo de conta bancária do Fintrack para o ACCTTYPE do OFX
+func ofxAccountType(accountType string) string {
+	if accountType == "savings" {
+		return "SAVINGS"
+	}
+	return "CHECKING"
+}
+
+// Limita o texto a n caracteres
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
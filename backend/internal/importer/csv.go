@@ -0,0 +1,239 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/money"
+)
+
+// Linha de extrato já convertida, com os erros de validação encontrados
+type Row struct {
+	Line        int
+	Date        time.Time
+	Description string
+	Type        string // "income" ou "expense"
+	Amount      money.Amount
+	Category    string
+	FITID       string // Identificador da transação no banco (OFX)
+	Errors      []string
+}
+
+// Mapeamento das colunas do CSV. Colunas podem ser o nome no cabeçalho ou o índice (começando em 0).
+type CSVMapping struct {
+	Delimiter         rune
+	HasHeader         bool
+	DateColumn        string
+	DateFormat        string // Ex: "DD/MM/YYYY", "YYYY-MM-DD"
+	AmountColumn      string
+	AmountSign        string // "negative_expense" (padrão) ou "positive_expense"
+	DecimalSeparator  string // "." (padrão) ou ","
+	DescriptionColumn string
+	CategoryColumn    string
+}
+
+// Lê o CSV e converte cada linha segundo o mapeamento
+func ParseCSV(r io.Reader, mapping CSVMapping, maxRows int) ([]Row, error) {
+	if mapping.DecimalSeparator == "" {
+		mapping.DecimalSeparator = "."
+	}
+	if mapping.DecimalSeparator != "." && mapping.DecimalSeparator != "," {
+		return nil, errors.New("separador decimal inválido, use '.' ou ','")
+	}
+	if mapping.AmountSign == "" {
+		mapping.AmountSign = "negative_expense"
+	}
+	if mapping.DateFormat == "" {
+		mapping.DateFormat = "DD/MM/YYYY"
+	}
+	layout, err := dateLayout(mapping.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	if mapping.Delimiter != 0 {
+		reader.Comma = mapping.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// Guarda a linha de cada registro: o leitor pula linhas em branco sem contá-las
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV inválido: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	var header []string
+	if mapping.HasHeader && len(records) > 0 {
+		header = records[0]
+		records, lines = records[1:], lines[1:]
+	}
+	if len(records) > maxRows {
+		return nil, fmt.Errorf("arquivo excede o limite de %d linhas", maxRows)
+	}
+
+	// Resolve as colunas informadas para índices
+	dateIdx, err := columnIndex(header, mapping.DateColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	amountIdx, err := columnIndex(header, mapping.AmountColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	descriptionIdx, err := columnIndex(header, mapping.DescriptionColumn, false)
+	if err != nil {
+		return nil, err
+	}
+	categoryIdx, err := columnIndex(header, mapping.CategoryColumn, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(records))
+	for i, record := range records {
+		// Ignora linhas totalmente vazias
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := Row{Line: lines[i]}
+
+		if value, ok := field(record, dateIdx); !ok || value == "" {
+			row.Errors = append(row.Errors, "data ausente")
+		} else if date, err := time.Parse(layout, value); err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("data inválida: %q", value))
+		} else {
+			row.Date = date
+		}
+
+		if value, ok := field(record, amountIdx); !ok || value == "" {
+			row.Errors = append(row.Errors, "valor ausente")
+		} else if amount, err := ParseAmount(value, mapping.DecimalSeparator); err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("valor inválido: %q (%v)", value, err))
+		} else if amount == 0 {
+			row.Errors = append(row.Errors, "valor zerado")
+		} else {
+			negative := amount < 0
+			row.Amount = amount.Abs()
+			if negative == (mapping.AmountSign == "negative_expense") {
+				row.Type = "expense"
+			} else {
+				row.Type = "income"
+			}
+		}
+
+		if value, ok := field(record, descriptionIdx); ok {
+			row.Description = truncate(value, 255)
+		}
+		if value, ok := field(record, categoryIdx); ok {
+			row.Category = value
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Converte valores como "1.234,56", "-1,234.56", "R$ 10,00" ou "(10.00)" em centavos
+func ParseAmount(value, decimalSeparator string) (money.Amount, error) {
+	s := strings.TrimSpace(value)
+	s = strings.ReplaceAll(s, "R$", "")
+	s = strings.ReplaceAll(s, " ", "")
+	s = strings.ReplaceAll(s, " ", "")
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	if strings.HasSuffix(s, "-") {
+		negative = true
+		s = strings.TrimSuffix(s, "-")
+	}
+
+	// Remove separador de milhar e normaliza o decimal para ponto
+	if decimalSeparator == "," {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	amount, err := money.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount.Abs()
+	}
+
+	return amount, nil
+}
+
+// Converte formatos como "DD/MM/YYYY" para o layout de data do Go
+func dateLayout(format string) (string, error) {
+	replacer := strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02")
+	layout := replacer.Replace(strings.ToUpper(format))
+	if !strings.Contains(layout, "06") || !strings.Contains(layout, "01") || !strings.Contains(layout, "02") {
+		return "", fmt.Errorf("formato de data inválido: %q", format)
+	}
+	return layout, nil
+}
+
+// Resolve uma coluna pelo nome do cabeçalho (sem diferenciar maiúsculas) ou pelo índice
+func columnIndex(header []string, column string, required bool) (int, error) {
+	column = strings.TrimSpace(column)
+	if column == "" {
+		if required {
+			return -1, errors.New("coluna obrigatória não informada no mapeamento")
+		}
+		return -1, nil
+	}
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+
+	if idx, err := strconv.Atoi(column); err == nil && idx >= 0 {
+		return idx, nil
+	}
+
+	return -1, fmt.Errorf("coluna não encontrada: %q", column)
+}
+
+// Retorna o valor da coluna, se existir
+func field(record []string, idx int) (string, bool) {
+	if idx < 0 || idx >= len(record) {
+		return "", false
+	}
+	return strings.TrimSpace(record[idx]), true
+}
+
+// Limita o texto a n caracteres
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
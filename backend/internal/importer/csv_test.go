@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/daviolvr/Fintrack/internal/money"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value     string
+		separator string
+		want      money.Amount
+		err       bool
+	}{
+		{"1.234,56", ",", 123456, false},
+		{"-1,234.56", ".", -123456, false},
+		{"R$ 10,00", ",", 1000, false},
+		{"R$\u00a010,00", ",", 1000, false},
+		{"(10.00)", ".", -1000, false},
+		{"10.00-", ".", -1000, false},
+		{"0,5", ",", 50, false},
+		{"1,234", ".", 123400, false},
+		{"10,555", ",", 0, true},
+		{"abc", ".", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.value, tt.separator)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseAmount(%q, %q) erro = %v, esperado erro: %t", tt.value, tt.separator, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q, %q) = %d, esperado %d", tt.value, tt.separator, got, tt.want)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "Data;Descrição;Valor;Categoria\n" +
+		"05/03/2025;Mercado;-1.234,56;Alimentação\n" +
+		"\n" +
+		"06/03/2025;Salário;5.000,00;\n" +
+		"31/02/2025;Data ruim;10,00;\n" +
+		"07/03/2025;Zerado;0,00;\n"
+
+	rows, err := ParseCSV(strings.NewReader(input), CSVMapping{
+		Delimiter:         ';',
+		HasHeader:         true,
+		DateColumn:        "data",
+		AmountColumn:      "Valor",
+		DecimalSeparator:  ",",
+		DescriptionColumn: "1",
+		CategoryColumn:    "Categoria",
+	}, 100)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("%d linhas, esperado 4 (a linha vazia é ignorada)", len(rows))
+	}
+
+	first := rows[0]
+	if first.Line != 2 || first.Type != "expense" || first.Amount != 123456 ||
+		first.Description != "Mercado" || first.Category != "Alimentação" ||
+		first.Date.Format("2006-01-02") != "2025-03-05" || len(first.Errors) > 0 {
+		t.Errorf("primeira linha inesperada: %+v", first)
+	}
+	if rows[1].Line != 4 || rows[1].Type != "income" || rows[1].Amount != 500000 {
+		t.Errorf("receita inesperada: %+v", rows[1])
+	}
+	if len(rows[2].Errors) != 1 || !strings.Contains(rows[2].Errors[0], "data inválida") {
+		t.Errorf("esperado erro de data em %+v", rows[2])
+	}
+	if len(rows[3].Errors) != 1 || rows[3].Errors[0] != "valor zerado" {
+		t.Errorf("esperado erro de valor zerado em %+v", rows[3])
+	}
+}
+
+func TestParseCSVPositiveExpense(t *testing.T) {
+	rows, err := ParseCSV(strings.NewReader("2025-01-10,42.00\n2025-01-11,-3.50\n"), CSVMapping{
+		DateColumn:   "0",
+		DateFormat:   "YYYY-MM-DD",
+		AmountColumn: "1",
+		AmountSign:   "positive_expense",
+	}, 100)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if rows[0].Type != "expense" || rows[0].Amount != 4200 || rows[1].Type != "income" || rows[1].Amount != 350 {
+		t.Errorf("sinais inesperados: %+v", rows)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		mapping CSVMapping
+	}{
+		{"coluna inexistente", "data,valor\n01/01/2025,1\n", CSVMapping{HasHeader: true, DateColumn: "data", AmountColumn: "total"}},
+		{"coluna obrigatória ausente", "01/01/2025,1\n", CSVMapping{DateColumn: "0"}},
+		{"formato de data inválido", "01/01/2025,1\n", CSVMapping{DateColumn: "0", AmountColumn: "1", DateFormat: "DD/MM"}},
+		{"separador inválido", "01/01/2025,1\n", CSVMapping{DateColumn: "0", AmountColumn: "1", DecimalSeparator: ";"}},
+		{"limite de linhas", "01/01/2025,1\n02/01/2025,1\n", CSVMapping{DateColumn: "0", AmountColumn: "1"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseCSV(strings.NewReader(tt.input), tt.mapping, 1); err == nil {
+			t.Errorf("%s: esperado erro", tt.name)
+		}
+	}
+}
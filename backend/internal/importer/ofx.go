@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Lê um extrato OFX (SGML 1.x ou XML 2.x) e converte cada STMTTRN em uma linha
+func ParseOFX(r io.Reader, maxRows int) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	content := decodeOFX(data)
+
+	// Descarta o cabeçalho (SGML "OFXHEADER:100" ou declarações XML)
+	start := strings.Index(strings.ToUpper(content), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("arquivo OFX inválido")
+	}
+	content = content[start:]
+
+	var rows []Row
+	var current map[string]string
+	line := 0
+
+	// Percorre as tags. No SGML os elementos não são fechados, então o valor
+	// de uma tag é o texto até a próxima tag.
+	for len(content) > 0 {
+		open := strings.IndexByte(content, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(content[open:], '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.ToUpper(strings.TrimSpace(content[open+1 : open+end]))
+		content = content[open+end+1:]
+
+		next := strings.IndexByte(content, '<')
+		if next < 0 {
+			next = len(content)
+		}
+		value := strings.TrimSpace(html.UnescapeString(content[:next]))
+
+		switch {
+		case tag == "STMTTRN":
+			current = make(map[string]string)
+			line++
+		case tag == "/STMTTRN":
+			if current != nil {
+				if len(rows) >= maxRows {
+					return nil, fmt.Errorf("arquivo excede o limite de %d transações", maxRows)
+				}
+				rows = append(rows, ofxRow(line, current))
+				current = nil
+			}
+		case current != nil && !strings.HasPrefix(tag, "/") && value != "":
+			current[tag] = value
+		}
+	}
+
+	return rows, nil
+}
+
+// Converte os campos de um STMTTRN em linha
+func ofxRow(line int, fields map[string]string) Row {
+	row := Row{
+		Line:  line,
+		FITID: truncate(fields["FITID"], 255),
+	}
+
+	if row.FITID == "" {
+		row.Errors = append(row.Errors, "FITID ausente")
+	}
+
+	if value := fields["DTPOSTED"]; value == "" {
+		row.Errors = append(row.Errors, "data ausente")
+	} else if date, err := parseOFXDate(value); err != nil {
+		row.Errors = append(row.Errors, fmt.Sprintf("data inválida: %q", value))
+	} else {
+		row.Date = date
+	}
+
+	if value := fields["TRNAMT"]; value == "" {
+		row.Errors = append(row.Errors, "valor ausente")
+	} else {
+		// Alguns bancos brasileiros usam vírgula como separador decimal
+		separator := "."
+		if strings.Contains(value, ",") && !strings.Contains(value, ".") {
+			separator = ","
+		}
+
+		amount, err := ParseAmount(value, separator)
+		switch {
+		case err != nil:
+			row.Errors = append(row.Errors, fmt.Sprintf("valor inválido: %q (%v)", value, err))
+		case amount == 0:
+			row.Errors = append(row.Errors, "valor zerado")
+		case amount < 0:
+			row.Type, row.Amount = "expense", amount.Abs()
+		default:
+			row.Type, row.Amount = "income", amount
+		}
+	}
+
+	// Muitos bancos colocam o histórico em MEMO, outros em NAME
+	description := fields["MEMO"]
+	if description == "" {
+		description = fields["NAME"]
+	}
+	row.Description = truncate(description, 255)
+
+	return row
+}
+
+// Datas OFX têm o formato YYYYMMDD[HHMMSS[.XXX]][[-3:BRT]]; só o dia interessa
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("data curta demais")
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+// Converte para UTF-8 extratos em Latin-1/Windows-1252, comuns em bancos brasileiros
+func decodeOFX(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
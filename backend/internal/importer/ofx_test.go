@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+CHARSET:1252
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250305120000[-3:BRT]
+<TRNAMT>-45,90
+<FITID>A1
+<MEMO>Padaria &amp; Café
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250306
+<TRNAMT>1500.00
+<FITID>A2
+<NAME>Salário
+</STMTTRN>
+<STMTTRN>
+<DTPOSTED>2025
+<TRNAMT>0
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+func TestParseOFXSGML(t *testing.T) {
+	rows, err := ParseOFX(strings.NewReader(sgmlStatement), 100)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("%d linhas, esperado 3", len(rows))
+	}
+
+	first := rows[0]
+	if first.Type != "expense" || first.Amount != 4590 || first.FITID != "A1" ||
+		first.Description != "Padaria & Café" || first.Date.Format("2006-01-02") != "2025-03-05" || len(first.Errors) > 0 {
+		t.Errorf("primeira transação inesperada: %+v", first)
+	}
+	if rows[1].Type != "income" || rows[1].Amount != 150000 || rows[1].Description != "Salário" {
+		t.Errorf("segunda transação inesperada: %+v", rows[1])
+	}
+
+	// Sem FITID, data curta e valor zerado
+	if rows[2].Line != 3 || len(rows[2].Errors) != 3 {
+		t.Errorf("esperados 3 erros na terceira transação: %+v", rows[2])
+	}
+}
+
+func TestParseOFXXML(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><BANKTRANLIST>
+<STMTTRN><DTPOSTED>20251231</DTPOSTED><TRNAMT>-10.5</TRNAMT><FITID>X</FITID><NAME>Loja</NAME></STMTTRN>
+</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1></OFX>`
+
+	rows, err := ParseOFX(strings.NewReader(input), 100)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Amount != 1050 || rows[0].Type != "expense" || rows[0].Description != "Loja" {
+		t.Errorf("transação inesperada: %+v", rows)
+	}
+}
+
+func TestParseOFXLatin1(t *testing.T) {
+	// "Café" em Latin-1
+	input := "<OFX><STMTTRN><DTPOSTED>20250101<TRNAMT>1<FITID>L<MEMO>Caf\xe9</STMTTRN></OFX>"
+
+	rows, err := ParseOFX(strings.NewReader(input), 100)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if rows[0].Description != "Café" {
+		t.Errorf("descrição = %q, esperado %q", rows[0].Description, "Café")
+	}
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	if _, err := ParseOFX(strings.NewReader("não é OFX"), 100); err == nil {
+		t.Error("esperado erro para arquivo sem <OFX>")
+	}
+	if _, err := ParseOFX(strings.NewReader(sgmlStatement), 2); err == nil {
+		t.Error("esperado erro ao exceder o limite de transações")
+	}
+}
@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Mailer para desenvolvimento local e testes: não envia nada, apenas registra o email
+// no log ou, se Dir estiver definido, grava um arquivo .eml por mensagem.
+type LogMailer struct {
+	Dir string
+}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	if m.Dir == "" {
+		log.Printf("Email para %s\nAssunto: %s\n\n%s", to, subject, body)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", to, subject, body)
+
+	return os.WriteFile(filepath.Join(m.Dir, name), []byte(content), 0o644)
+}
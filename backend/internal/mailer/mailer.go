@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"os"
+)
+
+// Envio de emails transacionais (redefinição de senha, verificação de email)
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// Cria o mailer a partir do .env.
+// MAIL_DRIVER=smtp usa o servidor SMTP configurado; qualquer outro valor
+// (padrão) apenas registra os emails no log ou em arquivos em MAIL_LOG_DIR.
+func NewMailer() Mailer {
+	if os.Getenv("MAIL_DRIVER") == "smtp" {
+		return &SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		}
+	}
+
+	return &LogMailer{Dir: os.Getenv("MAIL_LOG_DIR")}
+}
@@ -2,39 +2,302 @@ package models
 
 import (
 	"time"
+
+	"github.com/daviolvr/Fintrack/internal/money"
 )
 
 type User struct {
-	ID           uint       `gorm:"primaryKey"`
-	FirstName    string     `gorm:"not null;size:100" json:"first_name"`
-	LastName     string     `gorm:"not null;size:100" json:"last_name"`
-	Email        string     `gorm:"unique;not null;size:100" json:"email"`
-	Password     string     `gorm:"column:password_hash;not null;size:255" json:"-"`
-	Balance      float64    `gorm:"default:0" json:"balance"`
-	FailedLogins uint       `gorm:"default:0" json:"failed_logins"`
-	LockedUntil  *time.Time `json:"locked_until,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID              uint       `gorm:"primaryKey"`
+	FirstName       string     `gorm:"not null;size:100" json:"first_name"`
+	LastName        string     `gorm:"not null;size:100" json:"last_name"`
+	Email           string     `gorm:"unique;not null;size:100" json:"email"`
+	Password        string     `gorm:"column:password_hash;not null;size:255" json:"-"`
+	FailedLogins    uint       `gorm:"default:0" json:"failed_logins"`
+	LockedUntil     *time.Time `json:"locked_until,omitempty"`
+	TOTPSecret      *string    `gorm:"column:totp_secret;size:64" json:"-"` // Segredo do 2FA (pendente até a confirmação)
+	TOTPEnabled     bool       `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`
+	TOTPLastCounter int64      `gorm:"column:totp_last_counter;default:0" json:"-"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Espaço financeiro compartilhado entre usuários (ex: uma casa). Contas, categorias,
+// transações e demais registros financeiros pertencem a um workspace.
+type Workspace struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"not null;size:100" json:"name"`
+	OwnerID   uint      `gorm:"not null;index" json:"owner_id"`
+	Owner     User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Personal  bool      `gorm:"not null;default:false" json:"personal"` // Criado no cadastro; usado quando nenhum workspace é informado
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Membro de um workspace e seu papel: "owner", "editor" ou "viewer"
+type WorkspaceMember struct {
+	ID          uint      `gorm:"primaryKey"`
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_workspace_member" json:"workspace_id"`
+	Workspace   Workspace `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_workspace_member" json:"user_id"`
+	User        User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Role        string    `gorm:"not null;size:10" json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Convite por email para entrar em um workspace. Apenas o hash do token é armazenado.
+type WorkspaceInvitation struct {
+	ID          uint       `gorm:"primaryKey"`
+	WorkspaceID uint       `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Email       string     `gorm:"not null;size:255" json:"email"`
+	Role        string     `gorm:"not null;size:10" json:"role"`
+	TokenHash   string     `gorm:"not null;uniqueIndex;size:64" json:"-"`
+	InvitedByID uint       `gorm:"not null" json:"invited_by_id"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Pessoa com quem as despesas do workspace são divididas. Pode estar vinculada a um usuário.
+type Participant struct {
+	ID          uint      `gorm:"primaryKey"`
+	WorkspaceID uint      `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      *uint     `json:"user_id,omitempty"` // Usuário vinculado (único por workspace)
+	Name        string    `gorm:"not null;size:100" json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Conta/carteira (ex: Conta corrente, Poupança, Cartão de crédito)
+type Account struct {
+	ID          uint         `gorm:"primaryKey"`
+	WorkspaceID uint         `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      *uint        `json:"user_id"` // Quem criou
+	User        User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"user"`
+	Name        string       `gorm:"not null;size:50" json:"name"`
+	Type        string       `gorm:"not null;size:20" json:"type"` // "checking", "savings", "cash" ou "credit_card"
+	Balance     money.Amount `gorm:"default:0" json:"balance"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
 }
 
 // Categoria da transação (ex: Alimentação, Transporte)
 type Category struct {
-	ID     uint   `gorm:"primaryKey"`
-	UserID uint   `gorm:"not null" json:"user_id"`
-	User   User   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user"`
-	Name   string `gorm:"not null;size:50" json:"name"`
+	ID          uint       `gorm:"primaryKey"`
+	WorkspaceID uint       `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      *uint      `json:"user_id"` // Quem criou
+	User        User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"user"`
+	ParentID    *uint      `gorm:"index" json:"parent_id"` // Categoria pai (nil = categoria raiz)
+	Parent      *Category  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	Name        string     `gorm:"not null;size:50" json:"name"`
+	Type        string     `gorm:"not null;size:10;default:both" json:"type"` // income, expense ou both
+	Color       string     `gorm:"not null;size:7" json:"color"`              // Hexadecimal (#RRGGBB)
+	Icon        string     `gorm:"not null;size:50" json:"icon"`
+	ArchivedAt  *time.Time `json:"archived_at"` // Arquivada: some das listagens mas segue válida no histórico
 }
 
-type Transaction struct {
+// Etiqueta livre que agrupa transações de categorias diferentes (ex.: "viagem-2026")
+type Tag struct {
 	ID          uint      `gorm:"primaryKey"`
-	UserID      uint      `gorm:"not null" json:"user_id"`
-	User        User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user"`
-	CategoryID  uint      `gorm:"not null" json:"category_id"`
-	Category    Category  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"category"`
-	Type        string    `gorm:"not null;size:20" json:"type"` // "income" ou "expense"
-	Amount      float64   `gorm:"not null" json:"amount"`
-	Description string    `gorm:"size:255" json:"description"`
-	Date        time.Time `gorm:"not null" json:"date"`
+	WorkspaceID uint      `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      *uint     `json:"user_id"` // Quem criou
+	Name        string    `gorm:"not null;size:50" json:"name"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Transaction struct {
+	ID          uint               `gorm:"primaryKey"`
+	WorkspaceID uint               `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace          `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      *uint              `json:"user_id"` // Quem criou
+	User        User               `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"user"`
+	AccountID   uint               `gorm:"not null" json:"account_id"`
+	Account     Account            `gorm:"constraint:OnUpdate:CASCADE;" json:"account"`
+	CategoryID  uint               `gorm:"not null" json:"category_id"`
+	Category    Category           `gorm:"constraint:OnUpdate:CASCADE;" json:"category"`
+	Type        string             `gorm:"not null;size:20" json:"type"` // "income" ou "expense"
+	Amount      money.Amount       `gorm:"not null" json:"amount"`
+	Description string             `gorm:"size:255" json:"description"`
+	Date        time.Time          `gorm:"not null" json:"date"`
+	RecurringID *uint              `json:"recurring_id,omitempty"`                       // Regra recorrente que gerou a transação
+	FITID       *string            `gorm:"column:fitid;size:255" json:"fitid,omitempty"` // ID da transação no extrato OFX do banco
+	SplitType   *string            `gorm:"size:10" json:"split_type,omitempty"`          // "equal", "exact", "percent" ou "shares"
+	PayerID     *uint              `json:"payer_id,omitempty"`                           // Participante que pagou (quem criou a transação)
+	ShareAmount *money.Amount      `json:"share_amount,omitempty"`                       // Parte de quem pagou; usada nos relatórios e orçamentos
+	Splits      []TransactionSplit `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"splits,omitempty"`
+	Tags        []Tag              `gorm:"many2many:transaction_tags;" json:"tags,omitempty"`
+	Highlight   string             `gorm:"-" json:"highlight,omitempty"` // Descrição com os termos buscados marcados
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// Parte de uma transação dividida que cabe a um participante
+type TransactionSplit struct {
+	ID            uint         `gorm:"primaryKey"`
+	TransactionID uint         `gorm:"not null;index" json:"transaction_id"`
+	ParticipantID uint         `gorm:"not null" json:"participant_id"`
+	Participant   Participant  `json:"participant"`
+	Amount        money.Amount `gorm:"not null" json:"amount"`
+}
+
+// Pagamento entre participantes que quita (total ou parcialmente) uma dívida
+type Settlement struct {
+	ID                uint         `gorm:"primaryKey"`
+	WorkspaceID       uint         `gorm:"not null;index" json:"workspace_id"`
+	Workspace         Workspace    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID            *uint        `json:"user_id"` // Quem registrou
+	FromParticipantID uint         `gorm:"not null" json:"from_participant_id"`
+	FromParticipant   Participant  `json:"from_participant"`
+	ToParticipantID   uint         `gorm:"not null" json:"to_participant_id"`
+	ToParticipant     Participant  `json:"to_participant"`
+	Amount            money.Amount `gorm:"not null" json:"amount"`
+	Date              time.Time    `gorm:"not null" json:"date"`
+	Note              string       `gorm:"size:255" json:"note"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+// Transferência entre duas contas do mesmo workspace (não conta como receita nem despesa)
+type Transfer struct {
+	ID            uint         `gorm:"primaryKey"`
+	WorkspaceID   uint         `gorm:"not null;index" json:"workspace_id"`
+	Workspace     Workspace    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID        *uint        `json:"user_id"` // Quem criou
+	User          User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"user"`
+	FromAccountID uint         `gorm:"not null" json:"from_account_id"`
+	FromAccount   Account      `gorm:"constraint:OnUpdate:CASCADE;" json:"from_account"`
+	ToAccountID   uint         `gorm:"not null" json:"to_account_id"`
+	ToAccount     Account      `gorm:"constraint:OnUpdate:CASCADE;" json:"to_account"`
+	Amount        money.Amount `gorm:"not null" json:"amount"`
+	Description   string       `gorm:"size:255" json:"description"`
+	Date          time.Time    `gorm:"not null" json:"date"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// Regra de transação recorrente (ex: aluguel, salário, assinaturas)
+type RecurringTransaction struct {
+	ID          uint         `gorm:"primaryKey"`
+	WorkspaceID uint         `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      *uint        `json:"user_id"` // Quem criou (nil se excluiu a conta); as ocorrências são geradas em seu nome
+	User        User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"user"`
+	AccountID   uint         `gorm:"not null" json:"account_id"`
+	Account     Account      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"account"`
+	CategoryID  uint         `gorm:"not null" json:"category_id"`
+	Category    Category     `gorm:"constraint:OnUpdate:CASCADE;" json:"category"`
+	Type        string       `gorm:"not null;size:20" json:"type"` // "income" ou "expense"
+	Amount      money.Amount `gorm:"not null" json:"amount"`
+	Description string       `gorm:"size:255" json:"description"`
+	Frequency   string       `gorm:"not null;size:10" json:"frequency"` // "daily", "weekly", "monthly" ou "yearly"
+	Interval    int          `gorm:"column:interval_count;not null;default:1" json:"interval"`
+	DayOfMonth  *int         `json:"day_of_month,omitempty"` // Usado em "monthly" e "yearly"
+	StartDate   time.Time    `gorm:"not null" json:"start_date"`
+	EndDate     *time.Time   `json:"end_date,omitempty"`
+	Count       *int         `gorm:"column:max_occurrences" json:"count,omitempty"` // Limite de ocorrências
+	Occurrences int          `gorm:"not null;default:0" json:"occurrences"`         // Ocorrências já geradas
+	NextRunDate *time.Time   `json:"next_run_date,omitempty"`                       // nil quando a regra terminou
+	Active      bool         `gorm:"not null;default:true" json:"active"`
+	LastError   string       `gorm:"size:255" json:"last_error,omitempty"` // Falha da última ocorrência que não pôde ser gerada
+	FailedAt    *time.Time   `json:"failed_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Orçamento mensal de uma categoria (ex: R$ 800/mês em Alimentação)
+type Budget struct {
+	ID          uint         `gorm:"primaryKey"`
+	WorkspaceID uint         `gorm:"not null;index" json:"workspace_id"`
+	Workspace   Workspace    `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      *uint        `json:"user_id"` // Quem criou
+	User        User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"user"`
+	CategoryID  uint         `gorm:"not null" json:"category_id"`
+	Category    Category     `gorm:"constraint:OnUpdate:CASCADE;" json:"category"`
+	Limit       money.Amount `gorm:"column:limit_amount;not null" json:"limit"`
+	StartMonth  time.Time    `gorm:"not null" json:"start_month"`            // Primeiro dia do mês inicial
+	EndMonth    *time.Time   `json:"end_month,omitempty"`                    // Primeiro dia do mês final (nil = sem fim)
+	Rollover    bool         `gorm:"not null;default:false" json:"rollover"` // Acumula o valor não gasto para o mês seguinte
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Refresh token emitido. Cada login inicia uma família; a cada /refresh o token
+// atual é revogado e substituído por um novo da mesma família.
+type RefreshToken struct {
+	ID         uint       `gorm:"primaryKey"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	User       User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	JTI        string     `gorm:"column:jti;not null;uniqueIndex;size:64" json:"-"`
+	FamilyID   string     `gorm:"not null;index;size:64" json:"-"`
+	ReplacedBy *string    `gorm:"size:64" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Código de recuperação do 2FA (uso único). Apenas o hash é armazenado.
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	User      User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	CodeHash  string     `gorm:"not null;size:64" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Token de uso único enviado por email (redefinição de senha, verificação de email).
+// Apenas o hash é armazenado.
+type UserToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	User      User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Purpose   string     `gorm:"not null;size:30" json:"purpose"` // "password_reset" ou "email_verification"
+	TokenHash string     `gorm:"not null;uniqueIndex;size:64" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Chave de API pessoal para scripts e integrações. Apenas o hash da chave é armazenado.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	User       User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Name       string     `gorm:"not null;size:100" json:"name"`
+	Prefix     string     `gorm:"not null;size:16" json:"prefix"` // Início da chave, para identificá-la na listagem
+	KeyHash    string     `gorm:"not null;uniqueIndex;size:64" json:"-"`
+	Scope      string     `gorm:"not null;size:10" json:"scope"` // "read" ou "write"
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Sessão de login (um dispositivo/navegador). Agrupa a família de refresh tokens
+// emitida no login e é referenciada pelo "sid" dos access tokens.
+type Session struct {
+	ID         uint       `gorm:"primaryKey"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	User       User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	FamilyID   string     `gorm:"not null;uniqueIndex;size:64" json:"-"`
+	UserAgent  string     `gorm:"size:255" json:"user_agent"`
+	IP         string     `gorm:"column:ip;size:45" json:"ip"`
+	LastSeenAt time.Time  `gorm:"not null" json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Tentativa de login (bem-sucedida ou não). UserID fica nulo quando o email não existe.
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    *uint     `gorm:"index" json:"user_id,omitempty"`
+	User      *User     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Email     string    `gorm:"size:255" json:"email"`
+	Success   bool      `gorm:"not null" json:"success"`
+	Reason    string    `gorm:"size:50" json:"reason,omitempty"` // Motivo da falha, ex: "invalid_password"
+	IP        string    `gorm:"column:ip;size:45" json:"ip"`
+	UserAgent string    `gorm:"size:255" json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
 }
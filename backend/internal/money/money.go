@@ -0,0 +1,190 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Quantidade de centavos em uma unidade
+const Scale = 100
+
+var (
+	ErrInvalidAmount  = errors.New("valor inválido")
+	ErrTooManyDecimal = errors.New("valor com mais de duas casas decimais")
+)
+
+// Valor monetário exato, armazenado em centavos.
+//
+// Regras de arredondamento:
+//   - Entradas da API (JSON e query params) são rejeitadas se tiverem mais de 2 casas decimais
+//   - Valores vindos do banco com mais de 2 casas (ex: AVG) são arredondados
+//     para o centavo mais próximo, com empate afastando-se do zero
+type Amount int64
+
+// Converte um texto decimal (ex: "1234.56") exigindo no máximo 2 casas decimais
+func Parse(s string) (Amount, error) {
+	return parse(s, false)
+}
+
+// Converte um float para centavos, arredondando com empate afastando-se do zero
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * Scale))
+}
+
+// Retorna o valor como float (apenas para exibição/cálculos aproximados)
+func (a Amount) Float64() float64 {
+	return float64(a) / Scale
+}
+
+// Retorna o valor absoluto
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Formata com duas casas decimais e ponto como separador (ex: "-12.30")
+func (a Amount) String() string {
+	sign := ""
+	cents := int64(a)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/Scale, cents%Scale)
+}
+
+// Serializa como número JSON com duas casas decimais
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// Aceita número ou string JSON, rejeitando mais de duas casas decimais
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Envia ao banco como texto decimal, compatível com colunas NUMERIC
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Lê colunas NUMERIC (texto) ou numéricas do driver
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		parsed, err := parse(string(v), true)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case string:
+		parsed, err := parse(v, true)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case int64:
+		*a = Amount(v * Scale)
+		return nil
+	case float64:
+		*a = FromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("não é possível converter %T em money.Amount", src)
+}
+
+// Tipo da coluna usado pelo GORM
+func (Amount) GormDataType() string {
+	return "numeric"
+}
+
+// Faz o parse de um decimal; se round for true, arredonda casas extras em vez de rejeitar
+func parse(s string, round bool) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, ErrInvalidAmount
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, ErrInvalidAmount
+	}
+
+	// Casas decimais além dos centavos
+	roundUp := false
+	if len(fracPart) > 2 {
+		extra := strings.TrimRight(fracPart[2:], "0")
+		if extra != "" {
+			if !round {
+				return 0, ErrTooManyDecimal
+			}
+			roundUp = extra[0] >= '5'
+		}
+		fracPart = fracPart[:2]
+	}
+	for len(fracPart) < 2 {
+		fracPart += "0"
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units > math.MaxInt64/Scale-1 {
+		return 0, ErrInvalidAmount
+	}
+	cents, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	total := units*Scale + cents
+	if roundUp {
+		total++
+	}
+	if negative {
+		total = -total
+	}
+
+	return Amount(total), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
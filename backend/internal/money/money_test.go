@@ -0,0 +1,121 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  error
+	}{
+		{"0", 0, nil},
+		{"12", 1200, nil},
+		{"12.3", 1230, nil},
+		{"12.34", 1234, nil},
+		{" 12.34 ", 1234, nil},
+		{"-12.34", -1234, nil},
+		{"+0.01", 1, nil},
+		{".5", 50, nil},
+		{"7.", 700, nil},
+		{"1.2300", 123, nil},
+		{"0.001", 0, ErrTooManyDecimal},
+		{"1.005", 0, ErrTooManyDecimal},
+		{"", 0, ErrInvalidAmount},
+		{"-", 0, ErrInvalidAmount},
+		{".", 0, ErrInvalidAmount},
+		{"1,50", 0, ErrInvalidAmount},
+		{"1e3", 0, ErrInvalidAmount},
+		{"abc", 0, ErrInvalidAmount},
+		{"99999999999999999999", 0, ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) erro = %v, esperado %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("Parse(%q) = %d, esperado %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestScanRounding(t *testing.T) {
+	tests := []struct {
+		in   any
+		want Amount
+	}{
+		{"1.005", 101},
+		{"1.004", 100},
+		{"-1.005", -101},
+		{"-1.004", -100},
+		{[]byte("2.345"), 235},
+		{"0.995", 100},
+		{int64(3), 300},
+		{1.5, 150},
+		{nil, 0},
+	}
+
+	for _, tt := range tests {
+		var got Amount
+		if err := got.Scan(tt.in); err != nil {
+			t.Errorf("Scan(%v) erro inesperado: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Scan(%v) = %d, esperado %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  bool
+	}{
+		{`10.5`, 1050, false},
+		{`"10.50"`, 1050, false},
+		{`-0.01`, -1, false},
+		{`null`, 0, false},
+		{`10.555`, 0, true},
+		{`"abc"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		var got Amount
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.err {
+			t.Errorf("Unmarshal(%s) erro = %v, esperado erro: %t", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, esperado %d", tt.in, got, tt.want)
+		}
+	}
+
+	for _, a := range []Amount{0, 5, -5, 1234, -100050} {
+		data, err := json.Marshal(a)
+		if err != nil {
+			t.Fatalf("Marshal(%d): %v", a, err)
+		}
+		var back Amount
+		if err := json.Unmarshal(data, &back); err != nil || back != a {
+			t.Errorf("ida e volta de %d: %s -> %d (%v)", a, data, back, err)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[Amount]string{0: "0.00", 5: "0.05", -5: "-0.05", 1230: "12.30", -123456: "-1234.56"}
+	for in, want := range tests {
+		if got := in.String(); got != want {
+			t.Errorf("Amount(%d).String() = %q, esperado %q", in, got, want)
+		}
+	}
+}
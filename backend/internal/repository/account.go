@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cria conta no workspace
+func CreateAccount(db *gorm.DB, account *models.Account) error {
+	if err := db.Create(account).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Busca todas as contas do workspace
+func FindAccountsByWorkspace(db *gorm.DB, workspaceID uint) ([]models.Account, error) {
+	var accounts []models.Account
+
+	if err := db.Where("workspace_id = ?", workspaceID).Order("name").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// Busca uma única conta do workspace
+func FindAccountByIDAndWorkspaceID(db *gorm.DB, workspaceID, accountID uint) (*models.Account, error) {
+	var account models.Account
+
+	err := db.Where("id = ? AND workspace_id = ?", accountID, workspaceID).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// Atualiza nome e tipo da conta pelo ID e workspace_id
+func UpdateAccount(db *gorm.DB, account *models.Account) error {
+	result := db.Model(&models.Account{}).
+		Where("id = ? AND workspace_id = ?", account.ID, account.WorkspaceID).
+		Updates(map[string]interface{}{
+			"name": account.Name,
+			"type": account.Type,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Atualiza o saldo da conta pelo ID e workspace_id
+func UpdateAccountBalance(db *gorm.DB, account *models.Account) error {
+	result := db.Model(&models.Account{}).
+		Where("id = ? AND workspace_id = ?", account.ID, account.WorkspaceID).
+		Update("balance", account.Balance)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Deleta conta pelo ID e workspace_id
+func DeleteAccount(db *gorm.DB, id, workspaceID uint) error {
+	result := db.Where("id = ? AND workspace_id = ?", id, workspaceID).Delete(&models.Account{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Conta quantas transações estão vinculadas à conta
+func CountTransactionsByAccount(db *gorm.DB, accountID uint) (int64, error) {
+	var total int64
+
+	err := db.Model(&models.Transaction{}).
+		Where("account_id = ?", accountID).
+		Count(&total).Error
+
+	return total, err
+}
+
+// Conta as transferências em que a conta é origem ou destino
+func CountTransfersByAccount(db *gorm.DB, accountID uint) (int64, error) {
+	var total int64
+
+	err := db.Model(&models.Transfer{}).
+		Where("from_account_id = ? OR to_account_id = ?", accountID, accountID).
+		Count(&total).Error
+
+	return total, err
+}
+
+// Soma o saldo de todas as contas do workspace
+func SumAccountBalancesByWorkspace(db *gorm.DB, workspaceID uint) (money.Amount, error) {
+	var total money.Amount
+
+	err := db.Model(&models.Account{}).
+		Where("workspace_id = ?", workspaceID).
+		Select("COALESCE(SUM(balance), 0)").
+		Scan(&total).Error
+
+	return total, err
+}
+
+// Bloqueia as contas informadas do workspace (em ordem de ID, evitando deadlock) e as retorna indexadas pelo ID
+func lockAccounts(tx *gorm.DB, workspaceID uint, ids ...uint) (map[uint]*models.Account, error) {
+	locked, err := LockExistingAccounts(tx, workspaceID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, ok := locked[id]; !ok {
+			return nil, errors.New("conta não encontrada")
+		}
+	}
+
+	return locked, nil
+}
+
+// Bloqueia as contas do workspace que existirem entre os IDs, ignorando as demais
+func LockExistingAccounts(tx *gorm.DB, workspaceID uint, ids []uint) (map[uint]*models.Account, error) {
+	var accounts []models.Account
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND workspace_id = ?", ids, workspaceID).
+		Order("id").
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	locked := make(map[uint]*models.Account, len(accounts))
+	for i := range accounts {
+		locked[accounts[i].ID] = &accounts[i]
+	}
+
+	return locked, nil
+}
+
+// Soma o efeito líquido de um lote ao saldo das contas já bloqueadas, com uma única
+// atualização por conta. O saldo de cada item do lote já foi conferido por quem chama.
+func ApplyAccountBalances(tx *gorm.DB, accounts map[uint]*models.Account, net map[uint]money.Amount) error {
+	for id, amount := range net {
+		account, ok := accounts[id]
+		if !ok {
+			return errors.New("conta não encontrada")
+		}
+		if amount == 0 {
+			continue
+		}
+
+		account.Balance += amount
+		if err := tx.Model(account).Update("balance", account.Balance).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Cartões de crédito podem ficar com saldo negativo (fatura em aberto)
+func AllowsNegativeBalance(account *models.Account) bool {
+	return account.Type == "credit_card"
+}
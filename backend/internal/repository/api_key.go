@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+)
+
+// Cria chave de API
+func CreateAPIKey(db *gorm.DB, key *models.APIKey) error {
+	return db.Create(key).Error
+}
+
+// Lista as chaves de API do usuário (inclusive revogadas e expiradas)
+func FindAPIKeysByUser(db *gorm.DB, userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+
+	if err := db.Where("user_id = ?", userID).Order("created_at desc").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Busca uma chave ativa (não revogada e não expirada) pelo hash
+func FindActiveAPIKeyByHash(db *gorm.DB, keyHash string, now time.Time) (*models.APIKey, error) {
+	var key models.APIKey
+
+	if err := db.Where("key_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", keyHash, now).
+		First(&key).Error; err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// Atualiza o último uso da chave, no máximo uma vez por minuto para evitar uma escrita por requisição
+func TouchAPIKey(db *gorm.DB, id uint, now time.Time) error {
+	return db.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-time.Minute)).
+		Update("last_used_at", now).Error
+}
+
+// Revoga uma chave do usuário
+func RevokeAPIKey(db *gorm.DB, id, userID uint, now time.Time) error {
+	result := db.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", now)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"gorm.io/gorm"
+)
+
+// Total gasto em uma categoria em um mês
+type MonthlySpending struct {
+	CategoryID uint
+	Month      time.Time
+	Spent      money.Amount
+}
+
+// Cria orçamento
+func CreateBudget(db *gorm.DB, budget *models.Budget) error {
+	if err := db.Create(budget).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Busca os orçamentos do workspace
+func FindBudgetsByWorkspace(db *gorm.DB, workspaceID uint) ([]models.Budget, error) {
+	var budgets []models.Budget
+
+	if err := db.Where("workspace_id = ?", workspaceID).Order("start_month desc, id").Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// Busca os orçamentos de uma categoria do workspace
+func FindBudgetsByCategory(db *gorm.DB, workspaceID, categoryID uint) ([]models.Budget, error) {
+	var budgets []models.Budget
+
+	if err := db.Where("workspace_id = ? AND category_id = ?", workspaceID, categoryID).
+		Order("start_month, id").Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// Busca os orçamentos vigentes no mês, já com a categoria carregada.
+// Se houver mais de um para a mesma categoria, vale o de início mais recente.
+func FindActiveBudgetsByWorkspace(db *gorm.DB, workspaceID uint, month time.Time) ([]models.Budget, error) {
+	var budgets []models.Budget
+
+	err := db.Preload("Category").
+		Where("workspace_id = ? AND start_month <= ? AND (end_month IS NULL OR end_month >= ?)", workspaceID, month, month).
+		Order("category_id, start_month desc").
+		Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Mantém apenas o orçamento mais recente de cada categoria
+	active := make([]models.Budget, 0, len(budgets))
+	seen := make(map[uint]bool)
+	for _, b := range budgets {
+		if seen[b.CategoryID] {
+			continue
+		}
+		seen[b.CategoryID] = true
+		active = append(active, b)
+	}
+
+	return active, nil
+}
+
+// Atualiza orçamento pelo ID e workspace_id
+func UpdateBudget(db *gorm.DB, budget *models.Budget) error {
+	result := db.Model(&models.Budget{}).
+		Where("id = ? AND workspace_id = ?", budget.ID, budget.WorkspaceID).
+		Updates(map[string]interface{}{
+			"category_id":  budget.CategoryID,
+			"limit_amount": budget.Limit,
+			"start_month":  budget.StartMonth,
+			"end_month":    budget.EndMonth,
+			"rollover":     budget.Rollover,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Deleta orçamento pelo ID e workspace_id
+func DeleteBudget(db *gorm.DB, id, workspaceID uint) error {
+	result := db.Where("id = ? AND workspace_id = ?", id, workspaceID).Delete(&models.Budget{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Soma as despesas por categoria e mês no intervalo [from, to).
+// Os gastos das subcategorias contam para a categoria do orçamento.
+// Despesas divididas entram apenas com a parte de quem pagou.
+func SumExpensesByCategoryAndMonth(
+	db *gorm.DB,
+	workspaceID uint,
+	categoryIDs []uint,
+	from, to time.Time,
+) ([]MonthlySpending, error) {
+	var rows []MonthlySpending
+
+	if len(categoryIDs) == 0 {
+		return rows, nil
+	}
+
+	// budget_tree associa cada categoria orçada a ela mesma e às suas descendentes
+	err := db.Raw(`WITH RECURSIVE budget_tree AS (
+			SELECT id AS root_id, id FROM categories WHERE workspace_id = ? AND id IN ?
+			UNION
+			SELECT budget_tree.root_id, c.id FROM categories c JOIN budget_tree ON c.parent_id = budget_tree.id
+		)
+		SELECT budget_tree.root_id AS category_id, date_trunc('month', t.date)::date AS month,
+			SUM(COALESCE(t.share_amount, t.amount)) AS spent
+		FROM transactions t
+		JOIN budget_tree ON budget_tree.id = t.category_id
+		WHERE t.workspace_id = ? AND t.type = ? AND t.date >= ? AND t.date < ?
+		GROUP BY budget_tree.root_id, month`,
+		workspaceID, categoryIDs, workspaceID, "expense", from, to).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -1,11 +1,28 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/daviolvr/Fintrack/internal/models"
 	"gorm.io/gorm"
 )
 
-// Cria categoria para o usuário
+// Subconsulta com o ID da categoria informada (parâmetros: categoria e workspace) e de todas as suas descendentes.
+// UNION (e não UNION ALL) garante que a recursão termina mesmo se houver ciclo.
+const categorySubtreeSQL = `WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE id = ? AND workspace_id = ?
+	UNION
+	SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id
+) SELECT id FROM subtree`
+
+// Subconsulta que associa cada categoria do workspace à sua categoria raiz
+const categoryRootsSQL = `WITH RECURSIVE category_roots AS (
+	SELECT id, id AS root_id, name AS root_name FROM categories WHERE workspace_id = ? AND parent_id IS NULL
+	UNION
+	SELECT c.id, r.root_id, r.root_name FROM categories c JOIN category_roots r ON c.parent_id = r.id
+) SELECT id, root_id, root_name FROM category_roots`
+
+// Cria categoria no workspace
 func CreateCategory(db *gorm.DB, category *models.Category) error {
 	if err := db.Create(category).Error; err != nil {
 		return err
@@ -13,21 +30,34 @@ func CreateCategory(db *gorm.DB, category *models.Category) error {
 	return nil
 }
 
-// Busca categorias do usuário
-func FindCategoriesByUser(
+// Cria várias categorias de uma vez
+func CreateCategories(db *gorm.DB, categories []models.Category) error {
+	if err := db.Create(&categories).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Busca categorias do workspace. Arquivadas só entram com includeArchived.
+func FindCategoriesByWorkspace(
 	db *gorm.DB,
-	userID uint,
+	workspaceID uint,
 	search string,
+	includeArchived bool,
 	page, limit int,
 ) ([]models.Category, int, error) {
 	var categories []models.Category
 	var total int64
 
-	query := db.Model(&models.Category{}).Where("user_id = ?", userID)
+	query := db.Model(&models.Category{}).Where("workspace_id = ?", workspaceID)
+
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
 
 	// Filtro de busca
 	if search != "" {
-		query = query.Where("name ILIKIE ?", "%"+search+"%")
+		query = query.Where("name ILIKE ?", "%"+escapeLike(search)+"%")
 	}
 
 	// Conta o total de registros antes da paginação
@@ -44,11 +74,32 @@ func FindCategoriesByUser(
 	return categories, int(total), nil
 }
 
-// Atualiza categoria pelo ID e user_id
+// Busca uma única categoria do workspace
+func FindCategoryByIDAndWorkspaceID(db *gorm.DB, workspaceID, categoryID uint) (*models.Category, error) {
+	var category models.Category
+
+	if err := db.Where("id = ? AND workspace_id = ?", categoryID, workspaceID).First(&category).Error; err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Atualiza categoria pelo ID e workspace_id (o tipo só é alterado se informado)
 func UpdateCategory(db *gorm.DB, category *models.Category) error {
+	fields := map[string]any{
+		"name":      category.Name,
+		"parent_id": category.ParentID,
+		"color":     category.Color,
+		"icon":      category.Icon,
+	}
+	if category.Type != "" {
+		fields["type"] = category.Type
+	}
+
 	result := db.Model(&models.Category{}).
-		Where("id = ? AND user_id = ?", category.ID, category.UserID).
-		Update("name", category.Name)
+		Where("id = ? AND workspace_id = ?", category.ID, category.WorkspaceID).
+		Updates(fields)
 
 	if result.Error != nil {
 		return result.Error
@@ -60,9 +111,11 @@ func UpdateCategory(db *gorm.DB, category *models.Category) error {
 	return nil
 }
 
-// Deleta categoria pelo ID e user_id
-func DeleteCategory(db *gorm.DB, id, userID uint) error {
-	result := db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Category{})
+// Arquiva (archivedAt preenchido) ou desarquiva (nil) a categoria
+func SetCategoryArchivedAt(db *gorm.DB, id, workspaceID uint, archivedAt *time.Time) error {
+	result := db.Model(&models.Category{}).
+		Where("id = ? AND workspace_id = ?", id, workspaceID).
+		Update("archived_at", archivedAt)
 
 	if result.Error != nil {
 		return result.Error
@@ -73,3 +126,117 @@ func DeleteCategory(db *gorm.DB, id, userID uint) error {
 
 	return nil
 }
+
+// Verifica se já existe categoria com o nome no workspace, sem diferenciar
+// maiúsculas. excludeID ignora a própria categoria na atualização.
+func CategoryNameExists(db *gorm.DB, workspaceID uint, name string, excludeID uint) (bool, error) {
+	var total int64
+
+	if err := db.Model(&models.Category{}).
+		Where("workspace_id = ? AND lower(name) = lower(?) AND id <> ?", workspaceID, name, excludeID).
+		Count(&total).Error; err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+// Deleta categoria pelo ID e workspace_id
+func DeleteCategory(db *gorm.DB, id, workspaceID uint) error {
+	result := db.Where("id = ? AND workspace_id = ?", id, workspaceID).Delete(&models.Category{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Busca todas as categorias do workspace, sem paginação
+func FindAllCategoriesByWorkspace(db *gorm.DB, workspaceID uint) ([]models.Category, error) {
+	var categories []models.Category
+
+	if err := db.Where("workspace_id = ?", workspaceID).Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// Busca os IDs da categoria do workspace e de todas as suas subcategorias (em qualquer nível)
+func FindCategoryDescendantIDs(db *gorm.DB, workspaceID, categoryID uint) ([]uint, error) {
+	var ids []uint
+
+	if err := db.Raw(categorySubtreeSQL, categoryID, workspaceID).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Checa se a categoria tem transações, recorrências ou orçamentos
+func CategoryInUse(db *gorm.DB, categoryID uint) (bool, error) {
+	var inUse bool
+
+	err := db.Raw(`SELECT
+		EXISTS (SELECT 1 FROM transactions WHERE category_id = @id) OR
+		EXISTS (SELECT 1 FROM recurring_transactions WHERE category_id = @id) OR
+		EXISTS (SELECT 1 FROM budgets WHERE category_id = @id)`,
+		map[string]any{"id": categoryID}).
+		Scan(&inUse).Error
+
+	return inUse, err
+}
+
+// Checa se a categoria do workspace tem transações ou recorrências de tipo diferente de txType
+func CategoryHasOtherType(db *gorm.DB, workspaceID, categoryID uint, txType string) (bool, error) {
+	var exists bool
+
+	err := db.Raw(`SELECT
+		EXISTS (SELECT 1 FROM transactions
+			WHERE workspace_id = @workspace AND category_id = @id AND type <> @type) OR
+		EXISTS (SELECT 1 FROM recurring_transactions
+			WHERE workspace_id = @workspace AND category_id = @id AND type <> @type)`,
+		map[string]any{"workspace": workspaceID, "id": categoryID, "type": txType}).
+		Scan(&exists).Error
+
+	return exists, err
+}
+
+// Move transações, recorrências e orçamentos da categoria para targetID e a remove.
+// Com reparent as subcategorias passam para targetID; sem ele viram categorias raiz.
+// O destino não pode ter orçamento no mesmo mês inicial de um orçamento movido.
+func DeleteCategoryMovingTo(db *gorm.DB, id, workspaceID, targetID uint, reparent bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).
+			Where("category_id = ?", id).
+			Update("category_id", targetID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.RecurringTransaction{}).
+			Where("category_id = ?", id).
+			Update("category_id", targetID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Budget{}).
+			Where("category_id = ?", id).
+			Update("category_id", targetID).Error; err != nil {
+			return err
+		}
+
+		if reparent {
+			if err := tx.Model(&models.Category{}).
+				Where("parent_id = ?", id).
+				Update("parent_id", targetID).Error; err != nil {
+				return err
+			}
+		}
+
+		return DeleteCategory(tx, id, workspaceID)
+	})
+}
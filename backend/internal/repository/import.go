@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"gorm.io/gorm"
+)
+
+// Insere um lote de transações de uma mesma conta do workspace em uma única transação do banco.
+// O saldo da conta é atualizado uma única vez com o efeito líquido do lote.
+// Transações com FITID já importado na conta são ignoradas; retorna as que foram inseridas.
+func CreateTransactionsBatch(
+	db *gorm.DB,
+	workspaceID, userID, accountID uint,
+	transactions []models.Transaction,
+) ([]models.Transaction, error) {
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+
+	var created []models.Transaction
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Bloqueia a linha da conta uma única vez para todo o lote.
+		// O bloqueio também serializa importações concorrentes na mesma conta.
+		accounts, err := lockAccounts(tx, workspaceID, accountID)
+		if err != nil {
+			return err
+		}
+		account := accounts[accountID]
+
+		existing, err := findExistingFITIDs(tx, accountID, transactions)
+		if err != nil {
+			return err
+		}
+
+		var net money.Amount
+		created = make([]models.Transaction, 0, len(transactions))
+		for _, t := range transactions {
+			if t.FITID != nil && existing[*t.FITID] {
+				continue
+			}
+			t.WorkspaceID = workspaceID
+			t.UserID = &userID
+			t.AccountID = accountID
+			net += SignedAmount(t.Type, t.Amount)
+			created = append(created, t)
+		}
+		if len(created) == 0 {
+			return nil
+		}
+
+		// Checa se o saldo final da conta fica negativo
+		if account.Balance+net < 0 && !AllowsNegativeBalance(account) {
+			return fmt.Errorf("saldo insuficiente")
+		}
+
+		// Insere as transações em lotes
+		if err := tx.CreateInBatches(created, 500).Error; err != nil {
+			return err
+		}
+
+		// Atualiza saldo
+		if err := tx.Model(account).
+			Update("balance", gorm.Expr("balance + ?", net)).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// Retorna os FITIDs do lote que já existem na conta
+func findExistingFITIDs(tx *gorm.DB, accountID uint, transactions []models.Transaction) (map[string]bool, error) {
+	fitids := make([]string, 0, len(transactions))
+	for _, t := range transactions {
+		if t.FITID != nil {
+			fitids = append(fitids, *t.FITID)
+		}
+	}
+
+	existing := make(map[string]bool)
+	if len(fitids) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	if err := tx.Model(&models.Transaction{}).
+		Where("account_id = ? AND fitid IN ?", accountID, fitids).
+		Pluck("fitid", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, fitid := range found {
+		existing[fitid] = true
+	}
+
+	return existing, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+)
+
+// Registra uma tentativa de login
+func CreateLoginAttempt(db *gorm.DB, attempt *models.LoginAttempt) error {
+	return db.Create(attempt).Error
+}
+
+// Lista as tentativas de login do usuário, da mais recente para a mais antiga
+func FindLoginAttemptsByUser(db *gorm.DB, userID uint, page, limit int) ([]models.LoginAttempt, int, error) {
+	var attempts []models.LoginAttempt
+	var total int64
+
+	query := db.Model(&models.LoginAttempt{}).Where("user_id = ?", userID)
+
+	// Conta o total de registros antes da paginação
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Paginação e ordenação
+	offset := (page - 1) * limit
+	if err := query.Order("created_at desc, id desc").Limit(limit).Offset(offset).Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return attempts, int(total), nil
+}
+
+// Conta as falhas de login vindas de um IP desde "since", em qualquer conta. As tentativas
+// recusadas pelo próprio bloqueio do IP não contam, para que ele expire mesmo com novas tentativas.
+func CountFailedLoginsByIP(db *gorm.DB, ip string, since time.Time) (int64, error) {
+	var count int64
+
+	err := db.Model(&models.LoginAttempt{}).
+		Where("ip = ? AND success = FALSE AND reason IS DISTINCT FROM 'ip_throttled' AND created_at >= ?", ip, since).
+		Count(&count).Error
+
+	return count, err
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cria um participante
+func CreateParticipant(db *gorm.DB, participant *models.Participant) error {
+	return db.Create(participant).Error
+}
+
+// Busca os participantes do workspace
+func FindParticipantsByWorkspace(db *gorm.DB, workspaceID uint) ([]models.Participant, error) {
+	var participants []models.Participant
+
+	if err := db.Where("workspace_id = ?", workspaceID).
+		Order("name, id").
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
+// Busca os participantes do workspace com os IDs informados (IDs de outros workspaces são ignorados)
+func FindParticipantsByIDs(db *gorm.DB, workspaceID uint, ids []uint) ([]models.Participant, error) {
+	var participants []models.Participant
+
+	if err := db.Where("workspace_id = ? AND id IN ?", workspaceID, ids).
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
+// Busca o participante vinculado ao usuário no workspace
+func FindParticipantByUser(db *gorm.DB, workspaceID, userID uint) (*models.Participant, error) {
+	var participant models.Participant
+
+	if err := db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&participant).Error; err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+// Retorna o participante vinculado ao usuário, criando-o se ainda não existir
+func EnsureUserParticipant(db *gorm.DB, workspaceID, userID uint, name string) (*models.Participant, error) {
+	participant := models.Participant{
+		WorkspaceID: workspaceID,
+		UserID:      &userID,
+		Name:        name,
+	}
+
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "workspace_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&participant).Error; err != nil {
+		return nil, err
+	}
+
+	return FindParticipantByUser(db, workspaceID, userID)
+}
+
+// Checa se o participante do workspace aparece em alguma divisão, pagamento ou como pagador.
+// Retorna gorm.ErrRecordNotFound se o participante não pertence ao workspace.
+func ParticipantInUse(db *gorm.DB, workspaceID, participantID uint) (bool, error) {
+	var rows []bool
+
+	err := db.Raw(`SELECT
+		EXISTS (SELECT 1 FROM transaction_splits WHERE participant_id = @id) OR
+		EXISTS (SELECT 1 FROM transactions WHERE payer_id = @id) OR
+		EXISTS (SELECT 1 FROM settlements WHERE from_participant_id = @id OR to_participant_id = @id)
+		FROM participants WHERE id = @id AND workspace_id = @workspace`,
+		map[string]any{"id": participantID, "workspace": workspaceID}).
+		Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+	if len(rows) == 0 {
+		return false, gorm.ErrRecordNotFound
+	}
+
+	return rows[0], nil
+}
+
+// Deleta um participante do workspace
+func DeleteParticipant(db *gorm.DB, id, workspaceID uint) error {
+	result := db.Where("id = ? AND workspace_id = ?", id, workspaceID).
+		Delete(&models.Participant{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
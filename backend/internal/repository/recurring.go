@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+)
+
+// Cria regra recorrente
+func CreateRecurringTransaction(db *gorm.DB, r *models.RecurringTransaction) error {
+	if err := db.Create(r).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Busca as regras recorrentes do workspace
+func FindRecurringTransactionsByWorkspace(db *gorm.DB, workspaceID uint) ([]models.RecurringTransaction, error) {
+	var rules []models.RecurringTransaction
+
+	if err := db.Where("workspace_id = ?", workspaceID).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Busca uma regra recorrente do workspace
+func FindRecurringTransactionByIDAndWorkspaceID(db *gorm.DB, workspaceID, id uint) (*models.RecurringTransaction, error) {
+	var rule models.RecurringTransaction
+
+	if err := db.Where("id = ? AND workspace_id = ?", id, workspaceID).First(&rule).Error; err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// Atualiza uma regra recorrente pelo ID e workspace_id
+func UpdateRecurringTransaction(db *gorm.DB, r *models.RecurringTransaction) error {
+	result := db.Model(&models.RecurringTransaction{}).
+		Where("id = ? AND workspace_id = ?", r.ID, r.WorkspaceID).
+		Updates(map[string]interface{}{
+			"account_id":      r.AccountID,
+			"category_id":     r.CategoryID,
+			"type":            r.Type,
+			"amount":          r.Amount,
+			"description":     r.Description,
+			"frequency":       r.Frequency,
+			"interval_count":  r.Interval,
+			"day_of_month":    r.DayOfMonth,
+			"start_date":      r.StartDate,
+			"end_date":        r.EndDate,
+			"max_occurrences": r.Count,
+			"occurrences":     r.Occurrences,
+			"next_run_date":   r.NextRunDate,
+			"active":          r.Active,
+			"last_error":      r.LastError,
+			"failed_at":       r.FailedAt,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Deleta uma regra recorrente pelo ID e workspace_id
+func DeleteRecurringTransaction(db *gorm.DB, id, workspaceID uint) error {
+	result := db.Where("id = ? AND workspace_id = ?", id, workspaceID).Delete(&models.RecurringTransaction{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Busca regras ativas com ocorrências pendentes até a data informada
+func FindDueRecurringTransactions(db *gorm.DB, until time.Time) ([]models.RecurringTransaction, error) {
+	var rules []models.RecurringTransaction
+
+	err := db.Where("active AND next_run_date IS NOT NULL AND next_run_date <= ?", until).
+		Order("next_run_date, id").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Checa se a ocorrência da regra na data já foi gerada
+func RecurringOccurrenceExists(db *gorm.DB, recurringID uint, date time.Time) (bool, error) {
+	var total int64
+
+	err := db.Model(&models.Transaction{}).
+		Where("recurring_id = ? AND date = ?", recurringID, date).
+		Count(&total).Error
+
+	return total > 0, err
+}
+
+// Busca a data da última ocorrência gerada pela regra (nil se nenhuma foi gerada)
+func FindLastRecurringOccurrence(db *gorm.DB, recurringID uint) (*time.Time, error) {
+	var last sql.NullTime
+
+	if err := db.Model(&models.Transaction{}).
+		Select("MAX(date)").
+		Where("recurring_id = ?", recurringID).
+		Row().Scan(&last); err != nil {
+		return nil, err
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+
+	return &last.Time, nil
+}
+
+// Avança a regra para a próxima ocorrência, gravando também a situação (ativa e última falha).
+// Só atualiza se o contador ainda for o esperado, evitando avançar duas vezes a mesma ocorrência.
+func AdvanceRecurringTransaction(db *gorm.DB, r *models.RecurringTransaction, expectedOccurrences int) (bool, error) {
+	result := db.Model(&models.RecurringTransaction{}).
+		Where("id = ? AND occurrences = ?", r.ID, expectedOccurrences).
+		Updates(map[string]interface{}{
+			"occurrences":   r.Occurrences,
+			"next_run_date": r.NextRunDate,
+			"active":        r.Active,
+			"last_error":    r.LastError,
+			"failed_at":     r.FailedAt,
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Desativa as regras ativas criadas pelo usuário, registrando o motivo
+func DeactivateRecurringTransactionsByUser(db *gorm.DB, userID uint, reason string, at time.Time) error {
+	return db.Model(&models.RecurringTransaction{}).
+		Where("user_id = ? AND active", userID).
+		Updates(map[string]interface{}{
+			"active":     false,
+			"last_error": reason,
+			"failed_at":  at,
+		}).Error
+}
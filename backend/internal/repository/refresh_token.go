@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Salva um refresh token emitido
+func CreateRefreshToken(db *gorm.DB, token *models.RefreshToken) error {
+	return db.Create(token).Error
+}
+
+// Busca o refresh token pelo jti bloqueando a linha (evita rotação concorrente do mesmo token)
+func FindRefreshTokenForUpdate(db *gorm.DB, jti string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("jti = ?", jti).
+		First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Busca o refresh token pelo jti
+func FindRefreshToken(db *gorm.DB, jti string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+
+	if err := db.Where("jti = ?", jti).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Marca o token como substituído pelo novo jti
+func RotateRefreshToken(db *gorm.DB, id uint, replacedBy string, at time.Time) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"revoked_at":  at,
+			"replaced_by": replacedBy,
+		}).Error
+}
+
+// Revoga todos os tokens ainda ativos de uma família
+func RevokeRefreshTokenFamily(db *gorm.DB, familyID string, at time.Time) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+// Revoga todos os tokens ainda ativos do usuário
+func RevokeUserRefreshTokens(db *gorm.DB, userID uint, at time.Time) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
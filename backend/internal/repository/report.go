@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"gorm.io/gorm"
+)
+
+// Linha agregada do relatório: período + tipo (+ categoria, se solicitado)
+type SummaryRow struct {
+	Period       time.Time
+	Type         string
+	CategoryID   uint
+	CategoryName string
+	Total        money.Amount
+	Count        int
+}
+
+// Soma e conta as transações por período (day, week, month ou year) e tipo,
+// opcionalmente também por categoria. Com rollup, os gastos das subcategorias
+// são somados na categoria raiz. Despesas divididas entram apenas com a parte de quem pagou.
+func SummarizeTransactions(
+	db *gorm.DB,
+	workspaceID uint,
+	filter dto.TransactionFilter,
+	groupBy string,
+	byCategory, rollup bool,
+) ([]SummaryRow, error) {
+	var rows []SummaryRow
+
+	query := applyTransactionFilter(db.Model(&models.Transaction{}), workspaceID, filter)
+
+	columns := "date_trunc(?, transactions.date)::date AS period, transactions.type AS type, " +
+		"SUM(COALESCE(transactions.share_amount, transactions.amount)) AS total, COUNT(*) AS count"
+	groups := "period, transactions.type"
+
+	if byCategory && rollup {
+		columns += ", category_roots.root_id AS category_id, category_roots.root_name AS category_name"
+		groups += ", category_roots.root_id, category_roots.root_name"
+		query = query.Joins("JOIN ("+categoryRootsSQL+") AS category_roots ON category_roots.id = transactions.category_id", workspaceID)
+	} else if byCategory {
+		columns += ", transactions.category_id AS category_id, categories.name AS category_name"
+		groups += ", transactions.category_id, categories.name"
+		query = query.Joins("JOIN categories ON categories.id = transactions.category_id")
+	}
+
+	err := query.Select(columns, groupBy).
+		Group(groups).
+		Order("period").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
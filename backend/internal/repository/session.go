@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+)
+
+// Cria sessão
+func CreateSession(db *gorm.DB, session *models.Session) error {
+	return db.Create(session).Error
+}
+
+// Busca a sessão dona de uma família de refresh tokens
+func FindSessionByFamily(db *gorm.DB, familyID string) (*models.Session, error) {
+	var session models.Session
+
+	if err := db.Where("family_id = ?", familyID).First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Lista as sessões ativas do usuário, da mais recente para a mais antiga
+func FindActiveSessionsByUser(db *gorm.DB, userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+
+	if err := db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_seen_at desc").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Verifica se a sessão do usuário está ativa
+func IsSessionActive(db *gorm.DB, sessionID, userID uint) (bool, error) {
+	var count int64
+
+	if err := db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Atualiza o último acesso da sessão, no máximo uma vez por minuto
+func TouchSession(db *gorm.DB, sessionID uint, now time.Time) error {
+	return db.Model(&models.Session{}).
+		Where("id = ? AND last_seen_at < ?", sessionID, now.Add(-time.Minute)).
+		Update("last_seen_at", now).Error
+}
+
+// Revoga uma sessão do usuário e os refresh tokens da sua família
+func RevokeSession(db *gorm.DB, sessionID, userID uint, at time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var session models.Session
+
+		if err := tx.Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+			First(&session).Error; err != nil {
+			return err
+		}
+
+		return revokeSessionFamily(tx, session.FamilyID, at)
+	})
+}
+
+// Revoga a sessão de uma família de refresh tokens e todos os tokens dela
+func RevokeSessionByFamily(db *gorm.DB, familyID string, at time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return revokeSessionFamily(tx, familyID, at)
+	})
+}
+
+// Revoga todas as sessões e refresh tokens do usuário
+func RevokeUserSessions(db *gorm.DB, userID uint, at time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+
+		return RevokeUserRefreshTokens(tx, userID, at)
+	})
+}
+
+func revokeSessionFamily(tx *gorm.DB, familyID string, at time.Time) error {
+	if err := tx.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error; err != nil {
+		return err
+	}
+
+	return RevokeRefreshTokenFamily(tx, familyID, at)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"gorm.io/gorm"
+)
+
+// Valor total que um participante deve (ou pagou) a outro
+type DebtRow struct {
+	DebtorID   uint
+	CreditorID uint
+	Amount     money.Amount
+}
+
+// Soma, por par de participantes, as partes das despesas divididas que cada um deve a quem pagou
+func SumSplitDebts(db *gorm.DB, workspaceID uint) ([]DebtRow, error) {
+	var rows []DebtRow
+
+	err := db.Table("transaction_splits").
+		Select("transaction_splits.participant_id AS debtor_id, transactions.payer_id AS creditor_id, "+
+			"SUM(transaction_splits.amount) AS amount").
+		Joins("JOIN transactions ON transactions.id = transaction_splits.transaction_id").
+		Where("transactions.workspace_id = ? AND transaction_splits.participant_id <> transactions.payer_id", workspaceID).
+		Group("transaction_splits.participant_id, transactions.payer_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Soma, por par de participantes, os pagamentos registrados (de quem pagou para quem recebeu)
+func SumSettlements(db *gorm.DB, workspaceID uint) ([]DebtRow, error) {
+	var rows []DebtRow
+
+	err := db.Model(&models.Settlement{}).
+		Select("from_participant_id AS debtor_id, to_participant_id AS creditor_id, SUM(amount) AS amount").
+		Where("workspace_id = ?", workspaceID).
+		Group("from_participant_id, to_participant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Cria um pagamento entre participantes
+func CreateSettlement(db *gorm.DB, settlement *models.Settlement) error {
+	return db.Omit("FromParticipant", "ToParticipant").Create(settlement).Error
+}
+
+// Busca os pagamentos do workspace com paginação
+func FindSettlementsByWorkspace(db *gorm.DB, workspaceID uint, page, limit int) ([]models.Settlement, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	var settlements []models.Settlement
+	var total int64
+
+	query := db.Model(&models.Settlement{}).Where("workspace_id = ?", workspaceID)
+
+	// Contagem total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Paginação e ordenação
+	offset := (page - 1) * limit
+	if err := query.Preload("FromParticipant").Preload("ToParticipant").
+		Order("date desc, id desc").Limit(limit).Offset(offset).
+		Find(&settlements).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return settlements, int(total), nil
+}
+
+// Deleta um pagamento do workspace
+func DeleteSettlement(db *gorm.DB, id, workspaceID uint) error {
+	result := db.Where("id = ? AND workspace_id = ?", id, workspaceID).
+		Delete(&models.Settlement{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
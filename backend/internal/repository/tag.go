@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Linha agregada do relatório por etiqueta
+type TagTotalRow struct {
+	TagID   uint
+	TagName string
+	Type    string
+	Total   money.Amount
+	Count   int
+}
+
+// Cria uma etiqueta
+func CreateTag(db *gorm.DB, tag *models.Tag) error {
+	return db.Create(tag).Error
+}
+
+// Busca as etiquetas do workspace
+func FindTagsByWorkspace(db *gorm.DB, workspaceID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+
+	if err := db.Where("workspace_id = ?", workspaceID).
+		Order("lower(name)").
+		Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Atualiza o nome da etiqueta pelo ID e workspace_id
+func UpdateTag(db *gorm.DB, tag *models.Tag) error {
+	result := db.Model(&models.Tag{}).
+		Where("id = ? AND workspace_id = ?", tag.ID, tag.WorkspaceID).
+		Update("name", tag.Name)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Deleta a etiqueta (as associações com transações são removidas em cascata)
+func DeleteTag(db *gorm.DB, id, workspaceID uint) error {
+	result := db.Where("id = ? AND workspace_id = ?", id, workspaceID).Delete(&models.Tag{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Verifica se já existe etiqueta com o nome no workspace, sem diferenciar
+// maiúsculas. excludeID ignora a própria etiqueta na atualização.
+func TagNameExists(db *gorm.DB, workspaceID uint, name string, excludeID uint) (bool, error) {
+	var total int64
+
+	if err := db.Model(&models.Tag{}).
+		Where("workspace_id = ? AND lower(name) = lower(?) AND id <> ?", workspaceID, name, excludeID).
+		Count(&total).Error; err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+// Busca as etiquetas do workspace pelos nomes (sem diferenciar maiúsculas),
+// criando as que ainda não existem
+func FindOrCreateTags(db *gorm.DB, workspaceID, userID uint, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	var existing []models.Tag
+	if err := db.Where("workspace_id = ? AND lower(name) IN ?", workspaceID, lowered).
+		Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(existing))
+	for _, tag := range existing {
+		found[strings.ToLower(tag.Name)] = true
+	}
+
+	var missing []models.Tag
+	for _, name := range names {
+		if !found[strings.ToLower(name)] {
+			missing = append(missing, models.Tag{WorkspaceID: workspaceID, UserID: &userID, Name: name})
+			found[strings.ToLower(name)] = true
+		}
+	}
+	if len(missing) == 0 {
+		return existing, nil
+	}
+
+	// Outra requisição pode ter criado a mesma etiqueta: ignora o conflito e busca de novo
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := db.Where("workspace_id = ? AND lower(name) IN ?", workspaceID, lowered).
+		Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Soma e conta as transações de cada etiqueta por tipo, com os mesmos filtros da listagem.
+// Despesas divididas entram apenas com a parte de quem pagou.
+func SumTransactionsByTag(db *gorm.DB, workspaceID uint, filter dto.TransactionFilter) ([]TagTotalRow, error) {
+	var rows []TagTotalRow
+
+	err := applyTransactionFilter(db.Model(&models.Transaction{}), workspaceID, filter).
+		Select("tags.id AS tag_id, tags.name AS tag_name, transactions.type AS type, " +
+			"SUM(COALESCE(transactions.share_amount, transactions.amount)) AS total, COUNT(*) AS count").
+		Joins("JOIN transaction_tags ON transaction_tags.transaction_id = transactions.id").
+		Joins("JOIN tags ON tags.id = transaction_tags.tag_id").
+		Group("tags.id, tags.name, transactions.type").
+		Order("tags.name, tags.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
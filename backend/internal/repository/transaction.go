@@ -3,9 +3,12 @@ package repository
 import (
 	"errors"
 	"fmt"
-	"time"
+	"slices"
+	"strings"
 
+	"github.com/daviolvr/Fintrack/internal/dto"
 	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -13,32 +16,27 @@ import (
 // Cria transação
 func CreateTransaction(db *gorm.DB, t *models.Transaction) error {
 	return db.Transaction(func(tx *gorm.DB) error {
-		var user models.User
-
-		// Bloqueia a linha do usuário para atualizar saldo
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			First(&user, t.UserID).Error; err != nil {
+		// Bloqueia a linha da conta para atualizar saldo
+		accounts, err := lockAccounts(tx, t.WorkspaceID, t.AccountID)
+		if err != nil {
 			return err
 		}
+		account := accounts[t.AccountID]
 
 		// Checa saldo se for despesa
-		if t.Type == "expense" && user.Balance < t.Amount {
+		if t.Type == "expense" && !AllowsNegativeBalance(account) && account.Balance < t.Amount {
 			return fmt.Errorf("saldo insuficiente")
 		}
 
-		// Insere a transação
-		if err := tx.Create(t).Error; err != nil {
+		// Insere a transação (e a divisão e as etiquetas, se houver).
+		// As etiquetas já existem: só as associações são criadas.
+		if err := tx.Omit("Tags.*").Create(t).Error; err != nil {
 			return err
 		}
 
 		// Atualiza saldo
-		balanceChange := t.Amount
-		if t.Type == "expense" {
-			balanceChange = -t.Amount
-		}
-
-		if err := tx.Model(&user).
-			Update("balance", gorm.Expr("balance + ?", balanceChange)).Error; err != nil {
+		if err := tx.Model(account).
+			Update("balance", gorm.Expr("balance + ?", SignedAmount(t.Type, t.Amount))).Error; err != nil {
 			return err
 		}
 
@@ -46,14 +44,15 @@ func CreateTransaction(db *gorm.DB, t *models.Transaction) error {
 	})
 }
 
-// Busca uma única transação do usuário
-func RetrieveTransactionByIDAndUserID(
+// Busca uma única transação do workspace
+func RetrieveTransactionByIDAndWorkspaceID(
 	db *gorm.DB,
-	userID, transactionID uint,
+	workspaceID, transactionID uint,
 ) (*models.Transaction, error) {
 	var transaction models.Transaction
 
-	err := db.Where("id = ? AND user_id = ?", transactionID, userID).
+	err := db.Preload("Splits.Participant").Preload("Tags").
+		Where("id = ? AND workspace_id = ?", transactionID, workspaceID).
 		First(&transaction).Error
 
 	if err != nil {
@@ -66,16 +65,24 @@ func RetrieveTransactionByIDAndUserID(
 	return &transaction, nil
 }
 
-// Busca transação com filtros opcionais
-func FindTransactionsByUser(
+// Colunas aceitas na ordenação da listagem
+var transactionSortColumns = map[string]string{
+	"date":       "transactions.date",
+	"amount":     "transactions.amount",
+	"created_at": "transactions.created_at",
+}
+
+// Busca as transações do workspace com filtros opcionais. Sem cursor, pagina por
+// page/limit; com cursor, busca as transações depois (ou antes, se Backward) da
+// posição do cursor. Retorna também o total e se existem mais transações além da página.
+func FindTransactionsByWorkspace(
 	db *gorm.DB,
-	userID uint,
-	fromDate, toDate *time.Time,
-	categoryID *uint,
-	minAmount, maxAmount *float64,
-	txType *string,
+	workspaceID uint,
+	filter dto.TransactionFilter,
+	sort dto.TransactionSort,
+	cursor *dto.TransactionCursor,
 	page, limit int,
-) ([]models.Transaction, int, error) {
+) ([]models.Transaction, int, bool, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -86,52 +93,150 @@ func FindTransactionsByUser(
 	var transactions []models.Transaction
 	var total int64
 
-	// Monta a query base
-	query := db.Model(&models.Transaction{}).Where("user_id = ?", userID)
+	// Monta a query base com os filtros opcionais
+	query := applyTransactionFilter(db.Model(&models.Transaction{}), workspaceID, filter)
 
-	// Filtros opcionais
-	if fromDate != nil {
-		query = query.Where("date >= ?", *fromDate)
+	// Contagem total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, false, err
 	}
-	if toDate != nil {
-		query = query.Where("date <= ?", *toDate)
+
+	// Para voltar uma página, percorre a ordenação ao contrário a partir do cursor
+	desc := sort.Order != "asc"
+	if cursor != nil && cursor.Backward {
+		desc = !desc
 	}
-	if categoryID != nil {
-		query = query.Where("category_id = ?", *categoryID)
+
+	if cursor != nil {
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		query = query.Where(
+			"("+transactionSortColumns[sort.Field]+", transactions.id) "+op+" (?, ?)",
+			cursor.Value, cursor.ID,
+		)
+	} else {
+		query = query.Offset((page - 1) * limit)
 	}
-	if minAmount != nil {
-		query = query.Where("amount >= ?", *minAmount)
+
+	// Busca um registro a mais para saber se existe outra página
+	if err := query.Preload("Splits.Participant").Preload("Tags").
+		Order(transactionOrder(filter, sort.Field, desc)).Limit(limit + 1).Find(&transactions).Error; err != nil {
+		return nil, 0, false, err
 	}
-	if maxAmount != nil {
-		query = query.Where("amount >= ?", *minAmount)
+
+	hasMore := len(transactions) > limit
+	if hasMore {
+		transactions = transactions[:limit]
 	}
-	if txType != nil {
-		query = query.Where("type = ?", *txType)
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(transactions)
 	}
 
-	// Contagem total
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	return transactions, int(total), hasMore, nil
+}
+
+// Trechos SQL da busca textual na descrição (ver a configuração portuguese_unaccent nas migrations)
+const (
+	descriptionVectorSQL = "to_tsvector('portuguese_unaccent', coalesce(transactions.description, ''))"
+	descriptionFoldedSQL = "immutable_unaccent(lower(coalesce(transactions.description, '')))"
+	searchQuerySQL       = "websearch_to_tsquery('portuguese_unaccent', ?)"
+	similaritySQL        = "word_similarity(immutable_unaccent(lower(?)), " + descriptionFoldedSQL + ")"
+)
+
+// Marcadores usados pelo ts_headline, trocados por <mark> depois de escapar o HTML
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// Aplica o filtro de workspace e os filtros opcionais.
+// As colunas são qualificadas para permitir JOIN com outras tabelas.
+func applyTransactionFilter(query *gorm.DB, workspaceID uint, filter dto.TransactionFilter) *gorm.DB {
+	query = query.Where("transactions.workspace_id = ?", workspaceID)
+
+	if filter.FromDate != nil {
+		query = query.Where("transactions.date >= ?", *filter.FromDate)
+	}
+	if filter.ToDate != nil {
+		query = query.Where("transactions.date <= ?", *filter.ToDate)
+	}
+	if filter.CategoryID != nil {
+		// Inclui as subcategorias da categoria filtrada
+		query = query.Where("transactions.category_id IN ("+categorySubtreeSQL+")", *filter.CategoryID, workspaceID)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("transactions.amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("transactions.amount <= ?", *filter.MaxAmount)
+	}
+	if filter.Type != nil {
+		query = query.Where("transactions.type = ?", *filter.Type)
+	}
+	if filter.Query != "" {
+		// Texto completo (com stemming), trecho de palavra ou palavra parecida
+		query = query.Where("("+descriptionVectorSQL+" @@ "+searchQuerySQL+
+			" OR "+descriptionFoldedSQL+" LIKE '%' || immutable_unaccent(lower(?)) || '%'"+
+			" OR immutable_unaccent(lower(?)) <% "+descriptionFoldedSQL+")",
+			filter.Query, escapeLike(filter.Query), filter.Query)
+	}
+	if len(filter.Tags) > 0 {
+		tagged := "SELECT transaction_tags.transaction_id FROM transaction_tags " +
+			"JOIN tags ON tags.id = transaction_tags.tag_id WHERE lower(tags.name) IN ?"
+		if filter.TagMode == "all" {
+			// Só as transações que têm todas as etiquetas informadas
+			query = query.Where("transactions.id IN ("+tagged+
+				" GROUP BY transaction_tags.transaction_id HAVING COUNT(DISTINCT tags.id) = ?)",
+				filter.Tags, len(filter.Tags))
+		} else {
+			query = query.Where("transactions.id IN ("+tagged+")", filter.Tags)
+		}
 	}
 
-	// Paginação e ordenação
-	offset := (page - 1) * limit
-	if err := query.Order("date desc").Limit(limit).Offset(offset).Find(&transactions).Error; err != nil {
-		return nil, 0, err
+	return query
+}
+
+// Escapa os curingas do LIKE para buscar o texto literalmente
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Busca a descrição das transações com os termos da busca textual marcados
+// entre HighlightStart e HighlightStop (apenas termos encontrados pelo texto completo)
+func FindTransactionHighlights(db *gorm.DB, ids []uint, q string) (map[uint]string, error) {
+	var rows []struct {
+		ID        uint
+		Highlight string
 	}
 
-	return transactions, int(total), nil
+	options := "StartSel=" + HighlightStart + ", StopSel=" + HighlightStop + ", HighlightAll=true"
+	err := db.Model(&models.Transaction{}).
+		Select("id, ts_headline('portuguese_unaccent', coalesce(description, ''), "+searchQuerySQL+", ?) AS highlight",
+			q, options).
+		Where("id IN ?", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	highlights := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		highlights[row.ID] = row.Highlight
+	}
+
+	return highlights, nil
 }
 
-// Atualiza uma transação pertencente a um usuário
+// Atualiza uma transação pertencente a um workspace
 func UpdateTransaction(db *gorm.DB, t *models.Transaction) error {
 	return db.Transaction(func(tx *gorm.DB) error {
 		var oldTx models.Transaction
-		var user models.User
 
-		// Bloqueia a transação antiga para obter amount e type
+		// Bloqueia a transação antiga para obter conta, amount e type
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("id = ? AND user_id = ?", t.ID, t.UserID).
+			Where("id = ? AND workspace_id = ?", t.ID, t.WorkspaceID).
 			First(&oldTx).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return gorm.ErrRecordNotFound
@@ -139,33 +244,30 @@ func UpdateTransaction(db *gorm.DB, t *models.Transaction) error {
 			return err
 		}
 
-		// Bloqueia a linha do usuário para atualizar saldo
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			First(&user, t.UserID).Error; err != nil {
-			return err
+		// Bloqueia as contas envolvidas (a antiga e a nova podem ser diferentes)
+		accountIDs := []uint{oldTx.AccountID}
+		if t.AccountID != oldTx.AccountID {
+			accountIDs = append(accountIDs, t.AccountID)
 		}
-
-		// Remove efeito antigo da transação
-		if oldTx.Type == "income" {
-			user.Balance -= oldTx.Amount
-		} else {
-			user.Balance += oldTx.Amount
+		accounts, err := lockAccounts(tx, t.WorkspaceID, accountIDs...)
+		if err != nil {
+			return err
 		}
 
-		// Aplica novo valor da transação
-		if t.Type == "income" {
-			user.Balance += t.Amount
-		} else {
-			user.Balance -= t.Amount
-		}
+		// Remove efeito antigo da transação e aplica o novo valor
+		accounts[oldTx.AccountID].Balance -= SignedAmount(oldTx.Type, oldTx.Amount)
+		accounts[t.AccountID].Balance += SignedAmount(t.Type, t.Amount)
 
 		// Checa saldo negativo
-		if user.Balance < 0 {
-			return fmt.Errorf("saldo insuficiente")
+		for _, account := range accounts {
+			if account.Balance < 0 && !AllowsNegativeBalance(account) {
+				return fmt.Errorf("saldo insuficiente")
+			}
 		}
 
 		// Atualiza a transação
 		if err := tx.Model(&oldTx).Updates(models.Transaction{
+			AccountID:   t.AccountID,
 			CategoryID:  t.CategoryID,
 			Type:        t.Type,
 			Amount:      t.Amount,
@@ -175,24 +277,58 @@ func UpdateTransaction(db *gorm.DB, t *models.Transaction) error {
 			return err
 		}
 
-		// Atualiza saldo do usuário
-		if err := tx.Model(&user).Update("balance", user.Balance).Error; err != nil {
+		// Substitui a divisão da despesa
+		if err := replaceTransactionSplits(tx, &oldTx, t); err != nil {
+			return err
+		}
+
+		// Substitui as etiquetas
+		if err := tx.Model(&oldTx).Omit("Tags.*").Association("Tags").Replace(t.Tags); err != nil {
 			return err
 		}
 
+		// Atualiza saldo das contas
+		for _, account := range accounts {
+			if err := tx.Model(account).Update("balance", account.Balance).Error; err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
 
-// Deleta uma transação pelo ID e pelo userID
-func DeleteTransactionByUser(db *gorm.DB, userID uint, transactionID uint) error {
+// Troca a divisão da transação pela informada em t (sem divisão, apenas remove a atual)
+func replaceTransactionSplits(tx *gorm.DB, oldTx, t *models.Transaction) error {
+	if err := tx.Where("transaction_id = ?", oldTx.ID).Delete(&models.TransactionSplit{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(oldTx).Select("split_type", "payer_id", "share_amount").Updates(map[string]any{
+		"split_type":   t.SplitType,
+		"payer_id":     t.PayerID,
+		"share_amount": t.ShareAmount,
+	}).Error; err != nil {
+		return err
+	}
+
+	if len(t.Splits) == 0 {
+		return nil
+	}
+	for i := range t.Splits {
+		t.Splits[i].TransactionID = oldTx.ID
+	}
+	return tx.Omit("Participant").Create(&t.Splits).Error
+}
+
+// Deleta uma transação pelo ID e pelo workspaceID
+func DeleteTransactionByWorkspace(db *gorm.DB, workspaceID uint, transactionID uint) error {
 	return db.Transaction(func(tx *gorm.DB) error {
 		var transaction models.Transaction
-		var user models.User
 
-		// Bloqueia a transação para pegar amount e type
+		// Bloqueia a transação para pegar conta, amount e type
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("id = ? AND user_id = ?", transactionID, userID).
+			Where("id = ? AND workspace_id = ?", transactionID, workspaceID).
 			First(&transaction).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return gorm.ErrRecordNotFound
@@ -200,21 +336,18 @@ func DeleteTransactionByUser(db *gorm.DB, userID uint, transactionID uint) error
 			return err
 		}
 
-		// Bloqueia linha do usuário
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			First(&user, userID).Error; err != nil {
+		// Bloqueia linha da conta
+		accounts, err := lockAccounts(tx, workspaceID, transaction.AccountID)
+		if err != nil {
 			return err
 		}
+		account := accounts[transaction.AccountID]
 
 		// Remove efeito da transação do saldo
-		if transaction.Type == "income" {
-			user.Balance -= transaction.Amount
-		} else {
-			user.Balance += transaction.Amount
-		}
+		account.Balance -= SignedAmount(transaction.Type, transaction.Amount)
 
 		// Checa saldo negativo (opcional)
-		if user.Balance < 0 {
+		if account.Balance < 0 && !AllowsNegativeBalance(account) {
 			return fmt.Errorf("saldo insuficiente")
 		}
 
@@ -223,11 +356,140 @@ func DeleteTransactionByUser(db *gorm.DB, userID uint, transactionID uint) error
 			return err
 		}
 
-		// Atualiza saldo do usuário
-		if err := tx.Model(&user).Update("balance", user.Balance).Error; err != nil {
+		// Atualiza saldo da conta
+		if err := tx.Model(account).Update("balance", account.Balance).Error; err != nil {
 			return err
 		}
 
 		return nil
 	})
 }
+
+// Busca os IDs das transações do workspace que atendem ao filtro. Com ids, restringe a eles.
+func FindTransactionIDs(db *gorm.DB, workspaceID uint, filter dto.TransactionFilter, ids []uint) ([]uint, error) {
+	query := applyTransactionFilter(db.Model(&models.Transaction{}), workspaceID, filter)
+	if len(ids) > 0 {
+		query = query.Where("transactions.id IN ?", ids)
+	}
+
+	var found []uint
+	if err := query.Order("transactions.id").Pluck("transactions.id", &found).Error; err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// Bloqueia as transações do workspace pelos IDs e as retorna (com as etiquetas) indexadas pelo ID.
+// IDs inexistentes são ignorados.
+func LockTransactions(tx *gorm.DB, workspaceID uint, ids []uint) (map[uint]*models.Transaction, error) {
+	locked := make(map[uint]*models.Transaction, len(ids))
+	if len(ids) == 0 {
+		return locked, nil
+	}
+
+	var transactions []models.Transaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Tags").
+		Where("id IN ? AND workspace_id = ?", ids, workspaceID).
+		Order("id").
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range transactions {
+		locked[transactions[i].ID] = &transactions[i]
+	}
+
+	return locked, nil
+}
+
+// Insere uma transação (com divisão e etiquetas) sem alterar o saldo da conta.
+// Usada nas operações em lote, que ajustam os saldos uma única vez no final.
+func InsertTransaction(tx *gorm.DB, t *models.Transaction) error {
+	return tx.Omit("Tags.*").Create(t).Error
+}
+
+// Grava conta, categoria, descrição, data e etiquetas de uma transação sem alterar saldos
+func PatchTransaction(tx *gorm.DB, t *models.Transaction) error {
+	if err := tx.Model(t).
+		Select("account_id", "category_id", "description", "date", "updated_at").
+		Updates(t).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(t).Omit("Tags.*").Association("Tags").Replace(t.Tags)
+}
+
+// Remove uma transação sem alterar o saldo da conta (a divisão e as etiquetas saem em cascata)
+func RemoveTransaction(tx *gorm.DB, t *models.Transaction) error {
+	return tx.Delete(t).Error
+}
+
+// Retorna o efeito da transação no saldo (positivo para receita, negativo para despesa)
+func SignedAmount(txType string, amount money.Amount) money.Amount {
+	if txType == "expense" {
+		return -amount
+	}
+	return amount
+}
+
+// Percorre, com cursor, todas as transações do workspace que atendem ao filtro (sem paginação),
+// chamando fn para cada linha. Com byAccount, ordena por conta (cartões de crédito por último) antes da data.
+func StreamTransactionsByWorkspace(
+	db *gorm.DB,
+	workspaceID uint,
+	filter dto.TransactionFilter,
+	byAccount bool,
+	fn func(row *dto.TransactionExportRow) error,
+) error {
+	order := "transactions.date, transactions.id"
+	if byAccount {
+		// Contas bancárias antes dos cartões de crédito (no OFX ficam em conjuntos de mensagens separados)
+		order = "accounts.type = 'credit_card', transactions.account_id, " + order
+	}
+
+	rows, err := applyTransactionFilter(db.Model(&models.Transaction{}), workspaceID, filter).
+		Select("transactions.id, transactions.date, transactions.type, transactions.amount, " +
+			"transactions.description, transactions.account_id, accounts.name AS account_name, " +
+			"accounts.type AS account_type, transactions.category_id, categories.name AS category_name, " +
+			"transactions.fitid").
+		Joins("JOIN accounts ON accounts.id = transactions.account_id").
+		Joins("JOIN categories ON categories.id = transactions.category_id").
+		Order(order).
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row dto.TransactionExportRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Ordenação da listagem pela coluna escolhida, com o ID como desempate estável.
+// Sem coluna (apenas na busca textual), ordena pelos mais relevantes.
+func transactionOrder(filter dto.TransactionFilter, field string, desc bool) any {
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+
+	if field == "" {
+		return clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(" + descriptionVectorSQL + ", " + searchQuerySQL + ") + " + similaritySQL + " DESC, transactions.date DESC, transactions.id DESC",
+			Vars:               []any{filter.Query, filter.Query},
+			WithoutParentheses: true,
+		}}
+	}
+
+	return transactionSortColumns[field] + " " + dir + ", transactions.id " + dir
+}
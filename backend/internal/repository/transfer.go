@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cria transferência debitando a conta de origem e creditando a de destino
+func CreateTransfer(db *gorm.DB, t *models.Transfer) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Bloqueia as duas contas para atualizar saldo
+		accounts, err := lockAccounts(tx, t.WorkspaceID, t.FromAccountID, t.ToAccountID)
+		if err != nil {
+			return err
+		}
+		from := accounts[t.FromAccountID]
+		to := accounts[t.ToAccountID]
+
+		// Checa saldo da conta de origem
+		if !AllowsNegativeBalance(from) && from.Balance < t.Amount {
+			return fmt.Errorf("saldo insuficiente")
+		}
+
+		// Insere a transferência
+		if err := tx.Create(t).Error; err != nil {
+			return err
+		}
+
+		// Atualiza saldos
+		if err := tx.Model(from).
+			Update("balance", gorm.Expr("balance - ?", t.Amount)).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(to).
+			Update("balance", gorm.Expr("balance + ?", t.Amount)).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Busca transferências do workspace com paginação
+func FindTransfersByWorkspace(db *gorm.DB, workspaceID uint, page, limit int) ([]models.Transfer, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	var transfers []models.Transfer
+	var total int64
+
+	query := db.Model(&models.Transfer{}).Where("workspace_id = ?", workspaceID)
+
+	// Contagem total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Paginação e ordenação
+	offset := (page - 1) * limit
+	if err := query.Order("date desc, id desc").Limit(limit).Offset(offset).Find(&transfers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return transfers, int(total), nil
+}
+
+// Deleta uma transferência, desfazendo o efeito nos saldos
+func DeleteTransferByWorkspace(db *gorm.DB, workspaceID, transferID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var transfer models.Transfer
+
+		// Bloqueia a transferência
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND workspace_id = ?", transferID, workspaceID).
+			First(&transfer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return gorm.ErrRecordNotFound
+			}
+			return err
+		}
+
+		// Bloqueia as duas contas
+		accounts, err := lockAccounts(tx, workspaceID, transfer.FromAccountID, transfer.ToAccountID)
+		if err != nil {
+			return err
+		}
+		from := accounts[transfer.FromAccountID]
+		to := accounts[transfer.ToAccountID]
+
+		// Devolve o valor para a origem e retira do destino
+		from.Balance += transfer.Amount
+		to.Balance -= transfer.Amount
+
+		// Checa saldo negativo no destino
+		if to.Balance < 0 && !AllowsNegativeBalance(to) {
+			return fmt.Errorf("saldo insuficiente")
+		}
+
+		// Deleta a transferência
+		if err := tx.Delete(&transfer).Error; err != nil {
+			return err
+		}
+
+		// Atualiza saldos
+		for _, account := range accounts {
+			if err := tx.Model(account).Update("balance", account.Balance).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+)
+
+// Salva um novo segredo TOTP pendente (o 2FA só é ativado após a confirmação)
+func SetPendingTOTPSecret(db *gorm.DB, userID uint, secret string) error {
+	result := db.Model(&models.User{}).
+		Where("id = ? AND totp_enabled = ?", userID, false).
+		Updates(map[string]interface{}{
+			"totp_secret":       secret,
+			"totp_last_counter": 0,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Ativa o 2FA e grava os códigos de recuperação
+func EnableTOTP(db *gorm.DB, userID uint, counter int64, codeHashes []string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"totp_enabled":      true,
+				"totp_last_counter": counter,
+			}).Error; err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Desativa o 2FA e apaga segredo e códigos de recuperação
+func DisableTOTP(db *gorm.DB, userID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"totp_secret":       nil,
+				"totp_enabled":      false,
+				"totp_last_counter": 0,
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// Substitui todos os códigos de recuperação do usuário
+func ReplaceRecoveryCodes(db *gorm.DB, userID uint, codeHashes []string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]models.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+
+	return tx.Create(&codes).Error
+}
+
+// Registra o passo de tempo do código TOTP usado.
+// Retorna false se um código do mesmo passo (ou posterior) já foi usado, impedindo reuso.
+func UseTOTPCounter(db *gorm.DB, userID uint, counter int64) (bool, error) {
+	result := db.Model(&models.User{}).
+		Where("id = ? AND totp_last_counter < ?", userID, counter).
+		Update("totp_last_counter", counter)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Consome um código de recuperação ainda não usado
+func UseRecoveryCode(db *gorm.DB, userID uint, codeHash string) (bool, error) {
+	result := db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -66,22 +66,6 @@ func UpdateUser(db *gorm.DB, user *models.User) error {
 	return nil
 }
 
-// Atualiza o saldo da conta do usuário
-func UpdateUserBalance(db *gorm.DB, user *models.User) error {
-	result := db.Model(&models.User{}).
-		Where("id = ?", user.ID).
-		Update("balance", user.Balance)
-
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-
-	return nil
-}
-
 // Deleta o usuário
 func DeleteUser(db *gorm.DB, id uint) error {
 	result := db.Delete(&models.User{}, id)
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Salva um novo token, invalidando os tokens ainda não usados com a mesma finalidade
+func CreateUserToken(db *gorm.DB, token *models.UserToken) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(token).Error
+	})
+}
+
+// Marca como usado um token válido (não usado e não expirado).
+// Retorna gorm.ErrRecordNotFound se o token não existir, já tiver sido usado ou estiver expirado.
+func ConsumeUserToken(db *gorm.DB, purpose, tokenHash string) (*models.UserToken, error) {
+	var token models.UserToken
+	now := time.Now()
+
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&token).Update("used_at", now).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Marca o email do usuário como verificado
+func MarkEmailVerified(db *gorm.DB, userID uint, at time.Time) error {
+	result := db.Model(&models.User{}).
+		Where("id = ? AND email_verified_at IS NULL", userID).
+		Update("email_verified_at", at)
+
+	return result.Error
+}
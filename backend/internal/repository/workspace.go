@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Workspace com o papel do usuário que o consultou
+type WorkspaceWithRole struct {
+	models.Workspace
+	Role string
+}
+
+// Membro do workspace com os dados do usuário
+type WorkspaceMemberRow struct {
+	UserID    uint
+	FirstName string
+	LastName  string
+	Email     string
+	Role      string
+	CreatedAt time.Time
+}
+
+// Cria o workspace e adiciona o dono como membro
+func CreateWorkspace(db *gorm.DB, workspace *models.Workspace) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      workspace.OwnerID,
+			Role:        "owner",
+		}).Error
+	})
+}
+
+// Busca o workspace pessoal do usuário
+func FindPersonalWorkspace(db *gorm.DB, userID uint) (*models.Workspace, error) {
+	var workspace models.Workspace
+
+	if err := db.Where("owner_id = ? AND personal", userID).First(&workspace).Error; err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+// Busca um workspace pelo ID
+func FindWorkspaceByID(db *gorm.DB, id uint) (*models.Workspace, error) {
+	var workspace models.Workspace
+
+	if err := db.First(&workspace, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+// Lista os workspaces dos quais o usuário é membro, com o papel dele em cada um
+func FindWorkspacesByUser(db *gorm.DB, userID uint) ([]WorkspaceWithRole, error) {
+	var workspaces []WorkspaceWithRole
+
+	err := db.Model(&models.Workspace{}).
+		Select("workspaces.*, workspace_members.role AS role").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspaces.personal desc, workspaces.name").
+		Scan(&workspaces).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// Deleta o workspace (contas, categorias e transações são removidas em cascata)
+func DeleteWorkspace(db *gorm.DB, id uint) error {
+	result := db.Where("id = ? AND NOT personal", id).Delete(&models.Workspace{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Conta os workspaces do usuário que têm outros membros
+func CountSharedWorkspacesOwnedBy(db *gorm.DB, userID uint) (int64, error) {
+	var total int64
+
+	err := db.Model(&models.Workspace{}).
+		Where("owner_id = ? AND EXISTS (SELECT 1 FROM workspace_members "+
+			"WHERE workspace_members.workspace_id = workspaces.id AND workspace_members.user_id <> ?)",
+			userID, userID).
+		Count(&total).Error
+
+	return total, err
+}
+
+// Busca a participação do usuário no workspace
+func FindWorkspaceMember(db *gorm.DB, workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+
+	if err := db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error; err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// Lista os membros do workspace
+func FindWorkspaceMembers(db *gorm.DB, workspaceID uint) ([]WorkspaceMemberRow, error) {
+	var members []WorkspaceMemberRow
+
+	err := db.Model(&models.WorkspaceMember{}).
+		Select("workspace_members.user_id, users.first_name, users.last_name, users.email, "+
+			"workspace_members.role, workspace_members.created_at").
+		Joins("JOIN users ON users.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ?", workspaceID).
+		Order("workspace_members.created_at, workspace_members.id").
+		Scan(&members).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// Adiciona um membro. Se o usuário já participa, mantém o papel atual.
+func AddWorkspaceMember(db *gorm.DB, member *models.WorkspaceMember) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error
+}
+
+// Altera o papel de um membro (o dono não pode ser alterado)
+func UpdateWorkspaceMemberRole(db *gorm.DB, workspaceID, userID uint, role string) error {
+	result := db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ? AND role <> ?", workspaceID, userID, "owner").
+		Update("role", role)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Remove um membro (o dono não pode ser removido)
+func RemoveWorkspaceMember(db *gorm.DB, workspaceID, userID uint) error {
+	result := db.Where("workspace_id = ? AND user_id = ? AND role <> ?", workspaceID, userID, "owner").
+		Delete(&models.WorkspaceMember{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Salva um convite, invalidando convites pendentes para o mesmo email no workspace
+func CreateWorkspaceInvitation(db *gorm.DB, invitation *models.WorkspaceInvitation) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workspace_id = ? AND lower(email) = lower(?) AND accepted_at IS NULL",
+			invitation.WorkspaceID, invitation.Email).
+			Delete(&models.WorkspaceInvitation{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(invitation).Error
+	})
+}
+
+// Marca como aceito um convite válido (não aceito e não expirado).
+// Retorna gorm.ErrRecordNotFound se o convite não existir, já tiver sido aceito ou estiver expirado.
+func AcceptWorkspaceInvitation(db *gorm.DB, tokenHash string, at time.Time) (*models.WorkspaceInvitation, error) {
+	var invitation models.WorkspaceInvitation
+
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND accepted_at IS NULL AND expires_at > ?", tokenHash, at).
+		First(&invitation).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&invitation).Update("accepted_at", at).Error; err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/services"
+)
+
+// Agendador em processo que gera periodicamente as transações recorrentes vencidas
+type Scheduler struct {
+	recurring *services.RecurringService
+	interval  time.Duration
+	stop      chan struct{}
+}
+
+// Construtor
+func New(recurring *services.RecurringService, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		recurring: recurring,
+		interval:  interval,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Inicia o agendador em background.
+// A primeira execução é imediata, recuperando ocorrências perdidas enquanto o servidor esteve parado.
+func (s *Scheduler) Start() {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.run()
+		for {
+			select {
+			case <-ticker.C:
+				s.run()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Para o agendador
+func (s *Scheduler) Stop() {
+	close(s.stop)
+}
+
+func (s *Scheduler) run() {
+	created, err := s.recurring.ProcessDue(time.Now())
+	if err != nil {
+		log.Printf("Erro ao processar recorrências: %v", err)
+		return
+	}
+	if created > 0 {
+		log.Printf("Recorrências: %d transações geradas", created)
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrAccountInUse = errors.New("conta possui transações ou transferências vinculadas")
+
+type AccountService struct {
+	DB    *gorm.DB
+	cache *cache.Cache
+}
+
+// Construtor
+func NewAccountService(db *gorm.DB, cache *cache.Cache) *AccountService {
+	return &AccountService{DB: db, cache: cache}
+}
+
+// Cria uma conta
+func (s *AccountService) CreateAccount(userID, workspaceID uint, input dto.AccountCreateInput) (*models.Account, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &models.Account{
+		WorkspaceID: workspaceID,
+		UserID:      &userID,
+		Name:        input.Name,
+		Type:        input.Type,
+		Balance:     input.Balance,
+	}
+	if err := repository.CreateAccount(s.DB, account); err != nil {
+		return nil, err
+	}
+
+	// Invalida cache de contas do workspace
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
+
+	return account, nil
+}
+
+// Lista as contas do workspace
+func (s *AccountService) ListAccounts(userID, workspaceID uint) ([]models.Account, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("accounts:%d:list", workspaceID)
+
+	var cached dto.AccountCacheData
+	found, err := s.cache.Get(cacheKey, &cached)
+	if err == nil && found {
+		fmt.Println("Pegando do cache:", cacheKey)
+		return cached.Accounts, nil
+	}
+
+	accounts, err := repository.FindAccountsByWorkspace(s.DB, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Salva no cache
+	if err := s.cache.Set(cacheKey, dto.AccountCacheData{
+		Accounts: accounts,
+	}, time.Minute*5); err != nil {
+		fmt.Println("Erro ao salvar no cache:", err)
+	}
+
+	return accounts, nil
+}
+
+// Recupera uma conta
+func (s *AccountService) RetrieveAccount(userID, workspaceID, accountID uint) (*models.Account, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindAccountByIDAndWorkspaceID(s.DB, workspaceID, accountID)
+}
+
+// Atualiza nome e tipo de uma conta
+func (s *AccountService) UpdateAccount(userID, workspaceID, accountID uint, input dto.AccountInput) (*models.Account, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &models.Account{
+		ID:          accountID,
+		WorkspaceID: workspaceID,
+		Name:        input.Name,
+		Type:        input.Type,
+	}
+
+	if err := repository.UpdateAccount(s.DB, account); err != nil {
+		return nil, err
+	}
+
+	// Invalida cache de contas do workspace
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
+
+	return repository.FindAccountByIDAndWorkspaceID(s.DB, workspaceID, accountID)
+}
+
+// Ajusta manualmente o saldo de uma conta
+func (s *AccountService) UpdateBalance(userID, workspaceID, accountID uint, balance money.Amount) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	account := models.Account{
+		ID:          accountID,
+		WorkspaceID: workspaceID,
+		Balance:     balance,
+	}
+	if err := repository.UpdateAccountBalance(s.DB, &account); err != nil {
+		return err
+	}
+
+	return s.cache.InvalidateWorkspaceAccounts(workspaceID)
+}
+
+// Deleta uma conta sem transações ou transferências vinculadas
+func (s *AccountService) DeleteAccount(userID, workspaceID, accountID uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	if _, err := repository.FindAccountByIDAndWorkspaceID(s.DB, workspaceID, accountID); err != nil {
+		return err
+	}
+
+	total, err := repository.CountTransactionsByAccount(s.DB, accountID)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return ErrAccountInUse
+	}
+
+	// Transferências também referenciam a conta (como origem ou destino)
+	transfers, err := repository.CountTransfersByAccount(s.DB, accountID)
+	if err != nil {
+		return err
+	}
+	if transfers > 0 {
+		return ErrAccountInUse
+	}
+
+	if err := repository.DeleteAccount(s.DB, accountID, workspaceID); err != nil {
+		return err
+	}
+
+	return s.cache.InvalidateWorkspaceAccounts(workspaceID)
+}
+
+// Soma o saldo das contas
+func (s *AccountService) TotalBalance(accounts []models.Account) money.Amount {
+	var total money.Amount
+	for _, account := range accounts {
+		total += account.Balance
+	}
+	return total
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Prefixo que identifica as chaves de API do Fintrack
+const APIKeyPrefix = "fk_"
+
+var ErrInvalidAPIKey = errors.New("chave de API inválida")
+
+type APIKeyService struct {
+	DB *gorm.DB
+}
+
+// Construtor
+func NewAPIKeyService(db *gorm.DB) *APIKeyService {
+	return &APIKeyService{DB: db}
+}
+
+// Cria uma chave de API. A chave em texto puro só é retornada neste momento.
+func (s *APIKeyService) CreateAPIKey(userID uint, input dto.APIKeyInput) (*models.APIKey, string, error) {
+	var expiresAt *time.Time
+	if input.ExpiresAt != "" {
+		parsed, err := time.Parse("2006-01-02", input.ExpiresAt)
+		if err != nil {
+			return nil, "", errors.New("data de expiração inválida")
+		}
+		if !parsed.After(time.Now()) {
+			return nil, "", errors.New("data de expiração deve ser futura")
+		}
+		expiresAt = &parsed
+	}
+
+	scope := input.Scope
+	if scope == "" {
+		scope = "read"
+	}
+
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	plain := APIKeyPrefix + hex.EncodeToString(b)
+
+	key := &models.APIKey{
+		UserID:    userID,
+		Name:      input.Name,
+		Prefix:    plain[:len(APIKeyPrefix)+8],
+		KeyHash:   utils.HashToken(plain),
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := repository.CreateAPIKey(s.DB, key); err != nil {
+		return nil, "", err
+	}
+
+	return key, plain, nil
+}
+
+// Lista as chaves do usuário
+func (s *APIKeyService) ListAPIKeys(userID uint) ([]models.APIKey, error) {
+	return repository.FindAPIKeysByUser(s.DB, userID)
+}
+
+// Revoga uma chave do usuário
+func (s *APIKeyService) RevokeAPIKey(userID, id uint) error {
+	return repository.RevokeAPIKey(s.DB, id, userID, time.Now())
+}
+
+// Valida a chave recebida na requisição e retorna o usuário dono e o escopo.
+// Usado pelo AuthMiddleware.
+func (s *APIKeyService) Authenticate(plain string) (uint, string, error) {
+	if !strings.HasPrefix(plain, APIKeyPrefix) {
+		return 0, "", ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+
+	key, err := repository.FindActiveAPIKeyByHash(s.DB, utils.HashToken(plain), now)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", ErrInvalidAPIKey
+		}
+		return 0, "", err
+	}
+
+	// Falha ao registrar o uso não deve bloquear a requisição
+	if err := repository.TouchAPIKey(s.DB, key.ID, now); err != nil {
+		log.Printf("Erro ao atualizar último uso da chave de API %d: %v", key.ID, err)
+	}
+
+	return key.UserID, key.Scope, nil
+}
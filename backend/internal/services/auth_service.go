@@ -2,25 +2,60 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"os"
 	"time"
 
 	"github.com/daviolvr/Fintrack/internal/auth"
+	"github.com/daviolvr/Fintrack/internal/cache"
 	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/mailer"
 	"github.com/daviolvr/Fintrack/internal/models"
 	"github.com/daviolvr/Fintrack/internal/repository"
 	"github.com/daviolvr/Fintrack/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
+// Validade dos tokens enviados por email
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = time.Hour * 24
+)
+
+// Limite de falhas de login por IP, somando todas as contas, dentro da janela
+const (
+	maxFailedLoginsPerIP = 20
+	failedLoginIPWindow  = 15 * time.Minute
+)
+
+// Motivos de falha gravados no histórico de login
+const (
+	loginReasonUnknownEmail     = "unknown_email"
+	loginReasonInvalidPassword  = "invalid_password"
+	loginReasonAccountLocked    = "account_locked"
+	loginReasonEmailNotVerified = "email_not_verified"
+	loginReasonInvalidTwoFactor = "invalid_2fa_code"
+	loginReasonIPThrottled      = "ip_throttled"
+)
+
+var (
+	ErrInvalidRefreshToken  = errors.New("refresh token inválido")
+	ErrRefreshTokenReused   = errors.New("refresh token reutilizado, todas as sessões deste login foram encerradas")
+	ErrInvalidEmailToken    = errors.New("token inválido ou expirado")
+	ErrEmailNotVerified     = errors.New("email não verificado. Confira sua caixa de entrada")
+	ErrTooManyLoginAttempts = errors.New("muitas tentativas de login a partir deste endereço. Tente mais tarde")
+)
+
 type AuthService struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	cache  *cache.Cache
+	mailer mailer.Mailer
 }
 
-func NewAuthService(db *gorm.DB) *AuthService {
-	return &AuthService{DB: db}
+func NewAuthService(db *gorm.DB, cache *cache.Cache, mailer mailer.Mailer) *AuthService {
+	return &AuthService{DB: db, cache: cache, mailer: mailer}
 }
 
 func (s *AuthService) RegisterUser(input dto.RegisterInput) error {
@@ -40,75 +75,500 @@ func (s *AuthService) RegisterUser(input dto.RegisterInput) error {
 		Password:  hashedPassword,
 	}
 
-	return repository.CreateUser(s.DB, &user)
+	// O usuário, seu workspace pessoal e as categorias iniciais são criados juntos
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := repository.CreateUser(tx, &user); err != nil {
+			return err
+		}
+
+		workspace := models.Workspace{
+			Name:     "Pessoal",
+			OwnerID:  user.ID,
+			Personal: true,
+		}
+		if err := repository.CreateWorkspace(tx, &workspace); err != nil {
+			return err
+		}
+
+		return seedDefaultCategories(tx, workspace.ID, user.ID, input.Locale)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Falha no envio não impede o cadastro; o usuário pode pedir um novo email
+	if err := s.sendVerificationEmail(&user); err != nil {
+		log.Printf("Erro ao enviar email de verificação para o usuário %d: %v", user.ID, err)
+	}
+
+	return nil
 }
 
+// Autentica email e senha. Se o usuário tiver 2FA ativo, nenhum token é emitido:
+// retorna o token de desafio que deve ser trocado em /login/2fa junto com o código.
 func (s *AuthService) LoginUser(c *gin.Context, input dto.LoginInput) (*models.User, string, error) {
+	now := time.Now()
+
+	if err := s.checkIPThrottle(c, nil, input.Email, now); err != nil {
+		return nil, "", err
+	}
+
 	user, err := repository.FindUserByEmail(s.DB, input.Email)
 	if err != nil {
+		s.recordLoginAttempt(c, nil, input.Email, false, loginReasonUnknownEmail)
 		return nil, "", errors.New("email ou senha incorretos")
 	}
 
-	now := time.Now()
-
 	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		s.recordLoginAttempt(c, &user.ID, user.Email, false, loginReasonAccountLocked)
 		return nil, "", errors.New("conta bloqueada. Tente mais tarde")
 	}
 
 	if !utils.CheckPasswordHash(input.Password, user.Password) {
-		failed, _ := repository.IncrementFailedLogin(s.DB, user.ID)
-		if failed >= 5 {
-			repository.LockUser(s.DB, user.ID, now.Add(10*time.Minute))
-			return nil, "", errors.New("conta bloqueada. Tente novamente em 10 minutos")
+		s.recordLoginAttempt(c, &user.ID, user.Email, false, loginReasonInvalidPassword)
+		return nil, "", s.registerFailedLogin(user.ID, now, errors.New("email ou senha incorretos"))
+	}
+
+	if requireEmailVerification() && user.EmailVerifiedAt == nil {
+		s.recordLoginAttempt(c, &user.ID, user.Email, false, loginReasonEmailNotVerified)
+		return nil, "", ErrEmailNotVerified
+	}
+
+	// As falhas só são zeradas após o segundo fator, senão a senha correta
+	// permitiria tentar códigos TOTP sem nunca bloquear a conta
+	if user.TOTPEnabled {
+		challenge, err := auth.GenerateLoginChallenge(user.ID)
+		if err != nil {
+			return nil, "", err
 		}
-		return nil, "", errors.New("email ou senha incorretos")
+		return user, challenge, nil
 	}
 
 	repository.ResetFailedLogin(s.DB, user.ID)
 
-	accessToken, err := auth.GenerateJWT(user.ID)
-	if err != nil {
+	if err := s.startSession(c, user.ID); err != nil {
 		return nil, "", err
 	}
 
-	refreshToken, err := auth.GenerateRefreshToken(user.ID)
+	s.recordLoginAttempt(c, &user.ID, user.Email, true, "")
+
+	return user, "", nil
+}
+
+// Segunda etapa do login: troca o token de desafio e o código TOTP (ou de recuperação) pelos tokens
+func (s *AuthService) LoginTwoFactor(c *gin.Context, input dto.TwoFactorLoginInput) (*models.User, error) {
+	userID, err := auth.ParseLoginChallenge(input.ChallengeToken)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+
+	user, err := repository.FindUserByID(s.DB, userID)
+	if err != nil || !user.TOTPEnabled {
+		return nil, errors.New("desafio de login inválido ou expirado")
+	}
+
+	now := time.Now()
+
+	if err := s.checkIPThrottle(c, &user.ID, user.Email, now); err != nil {
+		return nil, err
+	}
+
+	if user.LockedUntil != nil && user.LockedUntil.After(now) {
+		s.recordLoginAttempt(c, &user.ID, user.Email, false, loginReasonAccountLocked)
+		return nil, errors.New("conta bloqueada. Tente mais tarde")
+	}
+
+	ok, err := verifySecondFactor(s.DB, user, input.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.recordLoginAttempt(c, &user.ID, user.Email, false, loginReasonInvalidTwoFactor)
+		return nil, s.registerFailedLogin(user.ID, now, ErrInvalidTwoFactorCode)
+	}
+
+	repository.ResetFailedLogin(s.DB, user.ID)
+
+	if err := s.startSession(c, user.ID); err != nil {
+		return nil, err
+	}
+
+	s.recordLoginAttempt(c, &user.ID, user.Email, true, "")
+
+	return user, nil
+}
+
+// Conta a falha de login e bloqueia a conta após 5 tentativas
+func (s *AuthService) registerFailedLogin(userID uint, now time.Time, loginErr error) error {
+	failed, _ := repository.IncrementFailedLogin(s.DB, userID)
+	if failed >= 5 {
+		repository.LockUser(s.DB, userID, now.Add(10*time.Minute))
+		return errors.New("conta bloqueada. Tente novamente em 10 minutos")
+	}
+	return loginErr
+}
+
+// Bloqueia temporariamente o IP que acumulou falhas demais, mesmo espalhadas
+// por vários emails (credential stuffing)
+func (s *AuthService) checkIPThrottle(c *gin.Context, userID *uint, email string, now time.Time) error {
+	failed, err := repository.CountFailedLoginsByIP(s.DB, c.ClientIP(), now.Add(-failedLoginIPWindow))
+	if err != nil {
+		return err
+	}
+	if failed >= maxFailedLoginsPerIP {
+		s.recordLoginAttempt(c, userID, email, false, loginReasonIPThrottled)
+		return ErrTooManyLoginAttempts
+	}
+	return nil
+}
+
+// Grava a tentativa no histórico de login. Falhas na gravação não interrompem o login.
+func (s *AuthService) recordLoginAttempt(c *gin.Context, userID *uint, email string, success bool, reason string) {
+	attempt := &models.LoginAttempt{
+		UserID:    userID,
+		Email:     truncateString(email, 255),
+		Success:   success,
+		Reason:    reason,
+		IP:        c.ClientIP(),
+		UserAgent: truncateString(c.Request.UserAgent(), 255),
+	}
+	if err := repository.CreateLoginAttempt(s.DB, attempt); err != nil {
+		log.Printf("Erro ao registrar tentativa de login: %v", err)
+	}
+}
+
+// Lista o histórico de tentativas de login do usuário
+func (s *AuthService) LoginHistory(userID uint, page, limit int) ([]models.LoginAttempt, int, error) {
+	return repository.FindLoginAttemptsByUser(s.DB, userID, page, limit)
+}
+
+// Cria a sessão do dispositivo (com uma nova família de refresh tokens) e grava os cookies de autenticação
+func (s *AuthService) startSession(c *gin.Context, userID uint) error {
+	familyID, err := auth.NewTokenID()
+	if err != nil {
+		return err
+	}
+
+	var accessToken, refreshToken string
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		session := &models.Session{
+			UserID:     userID,
+			FamilyID:   familyID,
+			UserAgent:  truncateString(c.Request.UserAgent(), 255),
+			IP:         c.ClientIP(),
+			LastSeenAt: time.Now(),
+		}
+		if err := repository.CreateSession(tx, session); err != nil {
+			return err
+		}
+
+		accessToken, refreshToken, _, err = s.issueTokens(tx, userID, session)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	setAuthCookies(c, accessToken, refreshToken)
+	return nil
+}
+
+// Rotaciona o refresh token: o token usado é revogado e um novo da mesma família é emitido.
+// Reutilizar um token já rotacionado revoga a família inteira.
+func (s *AuthService) RefreshToken(c *gin.Context, refreshToken string) (*dto.RefreshTokenResponse, error) {
+	userID, jti, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	var familyID, accessToken, newRefreshToken string
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		stored, err := repository.FindRefreshTokenForUpdate(tx, jti)
+		if err != nil || stored.UserID != userID {
+			return ErrInvalidRefreshToken
+		}
+		familyID = stored.FamilyID
+
+		if stored.RevokedAt != nil {
+			// Token já rotacionado sendo usado de novo: provável vazamento
+			if stored.ReplacedBy != nil {
+				return ErrRefreshTokenReused
+			}
+			return ErrInvalidRefreshToken
+		}
+		if stored.ExpiresAt.Before(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		session, err := repository.FindSessionByFamily(tx, familyID)
+		if err != nil || session.RevokedAt != nil {
+			return ErrInvalidRefreshToken
+		}
+		if err := repository.TouchSession(tx, session.ID, now); err != nil {
+			return err
+		}
+
+		var newJTI string
+		accessToken, newRefreshToken, newJTI, err = s.issueTokens(tx, userID, session)
+		if err != nil {
+			return err
+		}
+
+		return repository.RotateRefreshToken(tx, stored.ID, newJTI, now)
+	})
+	if err != nil {
+		// A revogação acontece fora da transação, que foi desfeita
+		if errors.Is(err, ErrRefreshTokenReused) {
+			if revokeErr := repository.RevokeSessionByFamily(s.DB, familyID, now); revokeErr != nil {
+				return nil, revokeErr
+			}
+		}
+		return nil, err
+	}
+
+	setAuthCookies(c, accessToken, newRefreshToken)
+
+	return &dto.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// Encerra a sessão do refresh token informado (revoga a sessão e sua família de tokens)
+func (s *AuthService) Logout(c *gin.Context, refreshToken string) error {
+	userID, jti, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return ErrInvalidRefreshToken
 	}
 
-	// Configura cookies HTTP-only
+	stored, err := repository.FindRefreshToken(s.DB, jti)
+	if err != nil || stored.UserID != userID {
+		return ErrInvalidRefreshToken
+	}
+
+	if err := repository.RevokeSessionByFamily(s.DB, stored.FamilyID, time.Now()); err != nil {
+		return err
+	}
+
+	clearAuthCookies(c)
+	return nil
+}
+
+// Encerra todas as sessões do usuário
+func (s *AuthService) LogoutAll(c *gin.Context, userID uint) error {
+	if err := repository.RevokeUserSessions(s.DB, userID, time.Now()); err != nil {
+		return err
+	}
+
+	clearAuthCookies(c)
+	return nil
+}
+
+// Gera access token e refresh token da sessão, persistindo o jti do refresh token.
+// Retorna os dois tokens e o jti do refresh token.
+func (s *AuthService) issueTokens(db *gorm.DB, userID uint, session *models.Session) (string, string, string, error) {
+	accessToken, err := auth.GenerateJWT(userID, session.ID)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	jti, err := auth.NewTokenID()
+	if err != nil {
+		return "", "", "", err
+	}
+	expiresAt := time.Now().Add(auth.RefreshTokenTTL)
+
+	refreshToken, err := auth.GenerateRefreshToken(userID, jti, expiresAt)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if err := repository.CreateRefreshToken(db, &models.RefreshToken{
+		UserID:    userID,
+		JTI:       jti,
+		FamilyID:  session.FamilyID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return "", "", "", err
+	}
+
+	return accessToken, refreshToken, jti, nil
+}
+
+// Configura cookies HTTP-only
+func setAuthCookies(c *gin.Context, accessToken, refreshToken string) {
 	c.SetCookie("access_token", accessToken, 3600, "/", "localhost", true, true)     // 1 hora
 	c.SetCookie("refresh_token", refreshToken, 259200, "/", "localhost", true, true) // 3 dias
+}
 
-	return user, "Login realizado com sucesso", nil
+// Remove os cookies de autenticação
+func clearAuthCookies(c *gin.Context) {
+	c.SetCookie("access_token", "", -1, "/", "localhost", true, true)
+	c.SetCookie("refresh_token", "", -1, "/", "localhost", true, true)
 }
 
-func (s *AuthService) RefreshToken(refreshToken string) (string, error) {
-	jwtRefreshSecret := []byte(os.Getenv("JWT_REFRESH_SECRET"))
+// Envia o link de redefinição de senha. Não revela se o email está cadastrado.
+func (s *AuthService) ForgotPassword(email string) error {
+	user, err := repository.FindUserByEmail(s.DB, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
 
-	// Valida o refresh token
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (any, error) {
-		return jwtRefreshSecret, nil
-	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
-	if err != nil || !token.Valid {
-		return "", errors.New("refresh token inválido")
+	token, err := s.createEmailToken(user.ID, "password_reset", passwordResetTTL)
+	if err != nil {
+		return err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", errors.New("refresh token inválido")
+	body := fmt.Sprintf(
+		"Olá, %s!\n\nRecebemos um pedido para redefinir sua senha no Fintrack. "+
+			"Para criar uma nova senha, acesse o link abaixo (válido por 1 hora):\n\n%s/reset-password?token=%s\n\n"+
+			"Se você não fez esse pedido, ignore este email.",
+		user.FirstName, frontendURL(), token,
+	)
+
+	return s.mailer.Send(user.Email, "Redefinição de senha - Fintrack", body)
+}
+
+// Redefine a senha com o token recebido por email e encerra todas as sessões do usuário
+func (s *AuthService) ResetPassword(token, newPassword string) error {
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
 	}
 
-	userIDFloat, ok := claims["user_id"].(float64)
-	if !ok {
-		return "", errors.New("refresh token malformado")
+	var userID uint
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		userToken, err := repository.ConsumeUserToken(tx, "password_reset", utils.HashToken(token))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidEmailToken
+			}
+			return err
+		}
+		userID = userToken.UserID
+
+		if err := repository.UpdatePassword(tx, &models.User{
+			ID:       userToken.UserID,
+			Password: hashedPassword,
+		}); err != nil {
+			return err
+		}
+
+		// Quem recebeu o email provou ter acesso à conta: remove o bloqueio por tentativas
+		if err := repository.ResetFailedLogin(tx, userToken.UserID); err != nil {
+			return err
+		}
+
+		// Receber o link também comprova o email
+		if err := repository.MarkEmailVerified(tx, userToken.UserID, time.Now()); err != nil {
+			return err
+		}
+
+		return repository.RevokeUserSessions(tx, userToken.UserID, time.Now())
+	})
+	if err != nil {
+		return err
 	}
 
-	// Gera novo access token
-	newAccessToken, err := auth.GenerateJWT(uint(userIDFloat))
+	// O usuário em cache ainda traz o bloqueio e a verificação antigos
+	return s.cache.InvalidateUserData(userID)
+}
+
+// Confirma o email com o token recebido
+func (s *AuthService) VerifyEmail(token string) error {
+	var userID uint
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		userToken, err := repository.ConsumeUserToken(tx, "email_verification", utils.HashToken(token))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidEmailToken
+			}
+			return err
+		}
+		userID = userToken.UserID
+
+		return repository.MarkEmailVerified(tx, userToken.UserID, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.cache.InvalidateUserData(userID)
+}
+
+// Reenvia o email de verificação. Não revela se o email está cadastrado.
+func (s *AuthService) ResendVerification(email string) error {
+	user, err := repository.FindUserByEmail(s.DB, email)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+func (s *AuthService) sendVerificationEmail(user *models.User) error {
+	token, err := s.createEmailToken(user.ID, "email_verification", emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Olá, %s!\n\nConfirme seu email no Fintrack acessando o link abaixo (válido por 24 horas):\n\n"+
+			"%s/verify-email?token=%s",
+		user.FirstName, frontendURL(), token,
+	)
+
+	return s.mailer.Send(user.Email, "Confirme seu email - Fintrack", body)
+}
+
+// Gera um token aleatório, salva apenas o hash e retorna o token em texto puro
+func (s *AuthService) createEmailToken(userID uint, purpose string, ttl time.Duration) (string, error) {
+	token, err := auth.NewTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	if err := repository.CreateUserToken(s.DB, &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
 		return "", err
 	}
 
-	return newAccessToken, nil
+	return token, nil
+}
+
+// Com REQUIRE_EMAIL_VERIFICATION=true o login só é liberado após confirmar o email
+func requireEmailVerification() bool {
+	return os.Getenv("REQUIRE_EMAIL_VERIFICATION") == "true"
+}
+
+// Endereço do frontend usado nos links enviados por email
+func frontendURL() string {
+	if url := os.Getenv("FRONTEND_URL"); url != "" {
+		return url
+	}
+	return "http://localhost:" + os.Getenv("FRONTEND_PORT")
+}
+
+// Limita o texto a n caracteres
+func truncateString(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
 }
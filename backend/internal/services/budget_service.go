@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BudgetService struct {
+	DB *gorm.DB
+}
+
+// Construtor
+func NewBudgetService(db *gorm.DB) *BudgetService {
+	return &BudgetService{DB: db}
+}
+
+// Cria um orçamento
+func (s *BudgetService) CreateBudget(userID, workspaceID uint, input dto.BudgetInput) (*models.Budget, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := s.buildBudget(userID, workspaceID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.CreateBudget(s.DB, budget); err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+// Lista os orçamentos do workspace
+func (s *BudgetService) ListBudgets(userID, workspaceID uint) ([]models.Budget, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindBudgetsByWorkspace(s.DB, workspaceID)
+}
+
+// Atualiza um orçamento
+func (s *BudgetService) UpdateBudget(userID, workspaceID, id uint, input dto.BudgetInput) (*models.Budget, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, err := s.buildBudget(userID, workspaceID, input)
+	if err != nil {
+		return nil, err
+	}
+	budget.ID = id
+
+	if err := repository.UpdateBudget(s.DB, budget); err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+// Deleta um orçamento
+func (s *BudgetService) DeleteBudget(userID, workspaceID, id uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	return repository.DeleteBudget(s.DB, id, workspaceID)
+}
+
+// Calcula gasto, saldo restante e percentual usado de cada orçamento vigente no mês
+func (s *BudgetService) Status(userID, workspaceID uint, month time.Time) (*dto.BudgetStatusResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	month = firstOfMonth(month)
+	nextMonth := month.AddDate(0, 1, 0)
+
+	budgets, err := repository.FindActiveBudgetsByWorkspace(s.DB, workspaceID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	// Busca gastos desde o início do orçamento mais antigo com rollover
+	from := month
+	categoryIDs := make([]uint, 0, len(budgets))
+	for _, b := range budgets {
+		categoryIDs = append(categoryIDs, b.CategoryID)
+		if b.Rollover && b.StartMonth.Before(from) {
+			from = b.StartMonth
+		}
+	}
+
+	rows, err := repository.SumExpensesByCategoryAndMonth(s.DB, workspaceID, categoryIDs, from, nextMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	spending := make(map[uint]map[string]money.Amount)
+	for _, row := range rows {
+		if spending[row.CategoryID] == nil {
+			spending[row.CategoryID] = make(map[string]money.Amount)
+		}
+		spending[row.CategoryID][row.Month.Format("2006-01")] = row.Spent
+	}
+
+	resp := &dto.BudgetStatusResponse{
+		Month: month.Format("2006-01"),
+		Data:  []dto.BudgetStatusItem{},
+	}
+
+	for _, b := range budgets {
+		spentByMonth := spending[b.CategoryID]
+
+		// Valor não gasto nos meses anteriores (nunca negativo)
+		var carry money.Amount
+		if b.Rollover {
+			for m := firstOfMonth(b.StartMonth); m.Before(month); m = m.AddDate(0, 1, 0) {
+				carry = b.Limit + carry - spentByMonth[m.Format("2006-01")]
+				if carry < 0 {
+					carry = 0
+				}
+			}
+		}
+
+		available := b.Limit + carry
+		spent := spentByMonth[month.Format("2006-01")]
+
+		resp.Data = append(resp.Data, dto.BudgetStatusItem{
+			BudgetID:       b.ID,
+			CategoryID:     b.CategoryID,
+			CategoryName:   b.Category.Name,
+			Limit:          b.Limit,
+			RolloverAmount: carry,
+			Available:      available,
+			Spent:          spent,
+			Remaining:      available - spent,
+			PercentageUsed: percentage(spent, available),
+		})
+
+		resp.TotalAvailable += available
+		resp.TotalSpent += spent
+	}
+	resp.TotalRemaining = resp.TotalAvailable - resp.TotalSpent
+
+	return resp, nil
+}
+
+// Monta o orçamento a partir do input, validando meses e categoria
+func (s *BudgetService) buildBudget(userID, workspaceID uint, input dto.BudgetInput) (*models.Budget, error) {
+	startMonth, err := time.Parse("2006-01", input.StartMonth)
+	if err != nil {
+		return nil, errors.New("mês inicial inválido")
+	}
+
+	var endMonth *time.Time
+	if input.EndMonth != "" {
+		parsed, err := time.Parse("2006-01", input.EndMonth)
+		if err != nil {
+			return nil, errors.New("mês final inválido")
+		}
+		if parsed.Before(startMonth) {
+			return nil, errors.New("mês final anterior ao mês inicial")
+		}
+		endMonth = &parsed
+	}
+
+	if _, err := checkCategory(s.DB, workspaceID, input.CategoryID); err != nil {
+		return nil, err
+	}
+
+	return &models.Budget{
+		WorkspaceID: workspaceID,
+		UserID:      &userID,
+		CategoryID:  input.CategoryID,
+		Limit:       input.Limit,
+		StartMonth:  startMonth,
+		EndMonth:    endMonth,
+		Rollover:    input.Rollover,
+	}, nil
+}
+
+// Primeiro dia do mês
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Percentual de "part" sobre "total", com duas casas decimais
+func percentage(part, total money.Amount) float64 {
+	if total <= 0 {
+		if part > 0 {
+			return 100
+		}
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
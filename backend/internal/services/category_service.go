@@ -1,8 +1,11 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/daviolvr/Fintrack/internal/cache"
@@ -12,6 +15,19 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCategoryParentNotFound = errors.New("categoria pai não encontrada")
+	ErrCategoryCycle          = errors.New("a categoria pai não pode ser a própria categoria nem uma de suas subcategorias")
+	ErrCategoryTargetNotFound = errors.New("categoria de destino não encontrada")
+	ErrCategorySameTarget     = errors.New("a categoria de destino deve ser diferente da categoria de origem")
+	ErrCategoryMergeIntoChild = errors.New("não é possível mesclar uma categoria em uma de suas subcategorias")
+	ErrCategoryInUse          = errors.New("a categoria possui transações, recorrências ou orçamentos; informe reassign_to para movê-los")
+	ErrCategoryExists         = errors.New("já existe uma categoria com esse nome")
+	ErrCategoryTypeMismatch   = errors.New("a categoria não aceita transações desse tipo")
+	ErrCategoryArchived       = errors.New("a categoria está arquivada")
+	ErrCategoryBudgetConflict = errors.New("a categoria de destino já tem orçamento nos meses")
+)
+
 type CategoryService struct {
 	DB    *gorm.DB
 	cache *cache.Cache
@@ -23,27 +39,55 @@ func NewCategoryService(db *gorm.DB, cache *cache.Cache) *CategoryService {
 }
 
 // Cria uma categoria
-func (s *CategoryService) CreateCategory(userID uint, name string) (*models.Category, error) {
+func (s *CategoryService) CreateCategory(userID, workspaceID uint, input dto.CategoryInput) (*models.Category, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCategoryParent(s.DB, workspaceID, 0, input.ParentID); err != nil {
+		return nil, err
+	}
+	if err := checkCategoryName(s.DB, workspaceID, 0, input.Name); err != nil {
+		return nil, err
+	}
+
+	categoryType := input.Type
+	if categoryType == "" {
+		categoryType = "both"
+	}
+
 	category := &models.Category{
-		UserID: userID,
-		Name:   name,
+		WorkspaceID: workspaceID,
+		UserID:      &userID,
+		ParentID:    input.ParentID,
+		Name:        input.Name,
+		Type:        categoryType,
+		Color:       input.Color,
+		Icon:        input.Icon,
 	}
 	if err := repository.CreateCategory(s.DB, category); err != nil {
 		return nil, err
 	}
 
-	// Invalida cache do usuário
-	s.cache.InvalidateUserCategories(userID)
+	// Invalida cache do workspace
+	s.cache.InvalidateWorkspaceCategories(workspaceID)
 
 	return category, nil
 }
 
-// Lista categorias com paginação e filtro
+// Lista categorias com paginação e filtro. Arquivadas só entram com includeArchived.
 func (s *CategoryService) ListCategories(
-	userID uint,
+	userID, workspaceID uint,
 	search string,
+	includeArchived bool,
 	page, limit int,
 ) ([]models.Category, int, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if page < 1 {
 		page = 1
 	}
@@ -52,7 +96,7 @@ func (s *CategoryService) ListCategories(
 	}
 
 	// Monta a chave do cache
-	cacheKey := fmt.Sprintf("categories:%d:%s:%d:%d", userID, search, page, limit)
+	cacheKey := fmt.Sprintf("categories:%d:%s:archived=%t:%d:%d", workspaceID, search, includeArchived, page, limit)
 
 	// Verifica se existe no cache
 	var cached dto.CategoryCacheData
@@ -63,7 +107,7 @@ func (s *CategoryService) ListCategories(
 	}
 
 	// Busca no banco
-	categories, total, err := repository.FindCategoriesByUser(s.DB, userID, search, page, limit)
+	categories, total, err := repository.FindCategoriesByWorkspace(s.DB, workspaceID, search, includeArchived, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -80,36 +124,334 @@ func (s *CategoryService) ListCategories(
 }
 
 // Atualiza uma categoria
-func (s *CategoryService) UpdateCategory(userID, id uint, name string) (*models.Category, error) {
+func (s *CategoryService) UpdateCategory(userID, workspaceID, id uint, input dto.CategoryInput) (*models.Category, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// A categoria precisa ser do workspace antes de qualquer consulta pelo ID (404 caso contrário)
+	if _, err := repository.FindCategoryByIDAndWorkspaceID(s.DB, workspaceID, id); err != nil {
+		return nil, err
+	}
+	if err := checkCategoryParent(s.DB, workspaceID, id, input.ParentID); err != nil {
+		return nil, err
+	}
+	if err := checkCategoryName(s.DB, workspaceID, id, input.Name); err != nil {
+		return nil, err
+	}
+
+	// Restringir o tipo exige que as transações e recorrências existentes sejam desse tipo
+	if input.Type != "" && input.Type != "both" {
+		mismatch, err := repository.CategoryHasOtherType(s.DB, workspaceID, id, input.Type)
+		if err != nil {
+			return nil, err
+		}
+		if mismatch {
+			return nil, fmt.Errorf("%w: a categoria possui transações ou recorrências de outro tipo", ErrCategoryTypeMismatch)
+		}
+	}
+
+	// Sem type, mantém o tipo atual
 	category := &models.Category{
-		ID:     id,
-		UserID: userID,
-		Name:   name,
+		ID:          id,
+		WorkspaceID: workspaceID,
+		ParentID:    input.ParentID,
+		Name:        input.Name,
+		Type:        input.Type,
+		Color:       input.Color,
+		Icon:        input.Icon,
 	}
 
 	if err := repository.UpdateCategory(s.DB, category); err != nil {
 		return nil, err
 	}
 
-	// Invalida cache do usuário
-	s.cache.InvalidateUserCategories(userID)
+	category, err = repository.FindCategoryByIDAndWorkspaceID(s.DB, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalida cache do workspace. Mudar a hierarquia altera os filtros por
+	// categoria e os relatórios, então as transações também são invalidadas.
+	s.cache.InvalidateWorkspaceCategories(workspaceID)
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
 
 	return category, nil
 }
 
-// Deleta uma categoria
-func (s *CategoryService) DeleteCategory(id, userID uint) error {
-	if err := repository.DeleteCategory(s.DB, id, userID); err != nil {
+// Arquiva ou desarquiva uma categoria. Arquivada, ela some das listagens e não
+// recebe novas transações, mas continua válida nas transações existentes.
+func (s *CategoryService) SetArchived(userID, workspaceID, id uint, archived bool) (*models.Category, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+
+	if err := repository.SetCategoryArchivedAt(s.DB, id, workspaceID, archivedAt); err != nil {
+		return nil, err
+	}
+
+	// Invalida cache do workspace
+	s.cache.InvalidateWorkspaceCategories(workspaceID)
+
+	return repository.FindCategoryByIDAndWorkspaceID(s.DB, workspaceID, id)
+}
+
+// Deleta uma categoria. Com reassignTo, as transações, recorrências e orçamentos
+// são movidos para a categoria de destino antes da remoção; sem ele, a remoção
+// é recusada se a categoria ainda tiver transações.
+func (s *CategoryService) DeleteCategory(userID, workspaceID, id uint, reassignTo *uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
 		return err
 	}
 
-	// Invalida cache do usuário
-	s.cache.InvalidateUserCategories(userID)
+	if _, err := repository.FindCategoryByIDAndWorkspaceID(s.DB, workspaceID, id); err != nil {
+		return err
+	}
+
+	if reassignTo != nil {
+		if err := checkCategoryTarget(s.DB, workspaceID, id, *reassignTo); err != nil {
+			return err
+		}
+		if err := repository.DeleteCategoryMovingTo(s.DB, id, workspaceID, *reassignTo, false); err != nil {
+			return err
+		}
+	} else {
+		inUse, err := repository.CategoryInUse(s.DB, id)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrCategoryInUse
+		}
+		if err := repository.DeleteCategory(s.DB, id, workspaceID); err != nil {
+			return err
+		}
+	}
+
+	// Invalida cache do workspace (as subcategorias viram raízes)
+	s.cache.InvalidateWorkspaceCategories(workspaceID)
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
 
 	return nil
 }
 
+// Mescla a categoria na categoria de destino: move transações, recorrências,
+// orçamentos e subcategorias e remove a categoria de origem
+func (s *CategoryService) MergeCategory(userID, workspaceID, id, targetID uint) (*models.Category, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repository.FindCategoryByIDAndWorkspaceID(s.DB, workspaceID, id); err != nil {
+		return nil, err
+	}
+	if err := checkCategoryTarget(s.DB, workspaceID, id, targetID); err != nil {
+		return nil, err
+	}
+
+	// As subcategorias passam para o destino, então ele não pode estar abaixo da origem
+	descendants, err := repository.FindCategoryDescendantIDs(s.DB, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(descendants, targetID) {
+		return nil, ErrCategoryMergeIntoChild
+	}
+
+	if err := repository.DeleteCategoryMovingTo(s.DB, id, workspaceID, targetID, true); err != nil {
+		return nil, err
+	}
+
+	// Invalida cache do workspace
+	s.cache.InvalidateWorkspaceCategories(workspaceID)
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
+
+	return repository.FindCategoryByIDAndWorkspaceID(s.DB, workspaceID, targetID)
+}
+
+// Monta a árvore de categorias do workspace. Sem includeArchived, as arquivadas
+// (e as subcategorias abaixo delas) ficam de fora.
+func (s *CategoryService) CategoryTree(userID, workspaceID uint, includeArchived bool) ([]dto.CategoryTreeResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := repository.FindAllCategoriesByWorkspace(s.DB, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agrupa as categorias pelo pai (0 = raiz), mantendo a ordem por nome
+	children := make(map[uint][]models.Category)
+	for _, category := range categories {
+		if category.ArchivedAt != nil && !includeArchived {
+			continue
+		}
+		var parentID uint
+		if category.ParentID != nil {
+			parentID = *category.ParentID
+		}
+		children[parentID] = append(children[parentID], category)
+	}
+
+	return buildCategoryTree(children, 0), nil
+}
+
+// Monta recursivamente os nós filhos do pai informado
+func buildCategoryTree(children map[uint][]models.Category, parentID uint) []dto.CategoryTreeResponse {
+	nodes := make([]dto.CategoryTreeResponse, 0, len(children[parentID]))
+	for _, category := range children[parentID] {
+		nodes = append(nodes, dto.CategoryTreeResponse{
+			ID:       category.ID,
+			Name:     category.Name,
+			Type:     category.Type,
+			Color:    category.Color,
+			Icon:     category.Icon,
+			Archived: category.ArchivedAt != nil,
+			Children: buildCategoryTree(children, category.ID),
+		})
+	}
+	return nodes
+}
+
 // Calcula total de páginas
 func (s *CategoryService) TotalPages(total, limit int) int {
 	return int(math.Ceil(float64(total) / float64(limit)))
 }
+
+// Garante que a categoria pertence ao workspace
+func checkCategory(db *gorm.DB, workspaceID, categoryID uint) (*models.Category, error) {
+	category, err := repository.FindCategoryByIDAndWorkspaceID(db, workspaceID, categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("categoria não encontrada")
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// Garante que a categoria aceita o tipo de transação (income ou expense) e,
+// sem allowArchived, que não está arquivada
+func checkCategoryUsage(category *models.Category, txType string, allowArchived bool) error {
+	if category.Type != "both" && category.Type != txType {
+		return fmt.Errorf("%w: %s aceita apenas %s", ErrCategoryTypeMismatch, category.Name, category.Type)
+	}
+	if category.ArchivedAt != nil && !allowArchived {
+		return fmt.Errorf("%w: %s", ErrCategoryArchived, category.Name)
+	}
+	return nil
+}
+
+// Garante que não existe outra categoria com o mesmo nome no workspace
+func checkCategoryName(db *gorm.DB, workspaceID, categoryID uint, name string) error {
+	exists, err := repository.CategoryNameExists(db, workspaceID, name, categoryID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrCategoryExists
+	}
+	return nil
+}
+
+// Garante que a categoria pai pertence ao workspace e não cria ciclo na
+// hierarquia. categoryID é 0 na criação.
+func checkCategoryParent(db *gorm.DB, workspaceID, categoryID uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	if _, err := repository.FindCategoryByIDAndWorkspaceID(db, workspaceID, *parentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryParentNotFound
+		}
+		return err
+	}
+
+	if categoryID == 0 {
+		return nil
+	}
+
+	// O pai não pode ser a própria categoria nem uma de suas descendentes
+	descendants, err := repository.FindCategoryDescendantIDs(db, workspaceID, categoryID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(descendants, *parentID) {
+		return ErrCategoryCycle
+	}
+
+	return nil
+}
+
+// Garante que a categoria de destino de uma realocação existe no workspace
+// e é diferente da categoria de origem
+func checkCategoryTarget(db *gorm.DB, workspaceID, categoryID, targetID uint) error {
+	if targetID == categoryID {
+		return ErrCategorySameTarget
+	}
+
+	target, err := repository.FindCategoryByIDAndWorkspaceID(db, workspaceID, targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryTargetNotFound
+		}
+		return err
+	}
+
+	// O destino precisa aceitar o tipo de todas as transações e recorrências movidas
+	if target.Type != "both" {
+		mismatch, err := repository.CategoryHasOtherType(db, workspaceID, categoryID, target.Type)
+		if err != nil {
+			return err
+		}
+		if mismatch {
+			return fmt.Errorf("%w: %s aceita apenas %s", ErrCategoryTypeMismatch, target.Name, target.Type)
+		}
+	}
+
+	// Os orçamentos movidos não podem coincidir com os do destino no mês inicial
+	source, err := repository.FindBudgetsByCategory(db, workspaceID, categoryID)
+	if err != nil {
+		return err
+	}
+	targetBudgets, err := repository.FindBudgetsByCategory(db, workspaceID, targetID)
+	if err != nil {
+		return err
+	}
+	if months := conflictingBudgetMonths(source, targetBudgets); len(months) > 0 {
+		return fmt.Errorf("%w %s", ErrCategoryBudgetConflict, strings.Join(months, ", "))
+	}
+
+	return nil
+}
+
+// Meses iniciais (AAAA-MM) em que as duas listas têm orçamento, em ordem
+func conflictingBudgetMonths(source, target []models.Budget) []string {
+	existing := make(map[string]bool, len(target))
+	for _, budget := range target {
+		existing[budget.StartMonth.Format("2006-01")] = true
+	}
+
+	var months []string
+	for _, budget := range source {
+		if month := budget.StartMonth.Format("2006-01"); existing[month] {
+			months = append(months, month)
+		}
+	}
+	slices.Sort(months)
+
+	return months
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+)
+
+func budgetsStarting(months ...string) []models.Budget {
+	budgets := make([]models.Budget, len(months))
+	for i, month := range months {
+		budgets[i] = models.Budget{StartMonth: mustDate(month + "-01")}
+	}
+	return budgets
+}
+
+func TestConflictingBudgetMonths(t *testing.T) {
+	tests := []struct {
+		name   string
+		source []models.Budget
+		target []models.Budget
+		want   []string
+	}{
+		{
+			name:   "sem orçamentos no destino",
+			source: budgetsStarting("2026-01", "2026-02"),
+			target: nil,
+			want:   nil,
+		},
+		{
+			name:   "meses diferentes não conflitam",
+			source: budgetsStarting("2026-01"),
+			target: budgetsStarting("2026-02", "2025-01"),
+			want:   nil,
+		},
+		{
+			name:   "meses coincidentes em ordem",
+			source: budgetsStarting("2026-03", "2026-01", "2026-02"),
+			target: budgetsStarting("2026-01", "2026-03"),
+			want:   []string{"2026-01", "2026-03"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := conflictingBudgetMonths(tt.source, tt.target)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("conflictingBudgetMonths = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
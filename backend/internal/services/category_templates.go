@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Idioma usado quando nenhum é informado no cadastro
+const DefaultCategoryLocale = "pt-BR"
+
+var ErrCategoryTemplateNotFound = errors.New("categoria modelo não encontrada")
+
+// Categorias modelo por idioma, separadas entre receitas e despesas
+var categoryTemplates = map[string][]dto.CategoryTemplateItem{
+	"pt-BR": {
+		{Name: "Alimentação", Type: "expense"},
+		{Name: "Transporte", Type: "expense"},
+		{Name: "Moradia", Type: "expense"},
+		{Name: "Saúde", Type: "expense"},
+		{Name: "Lazer", Type: "expense"},
+		{Name: "Educação", Type: "expense"},
+		{Name: "Compras", Type: "expense"},
+		{Name: "Contas e serviços", Type: "expense"},
+		{Name: "Salário", Type: "income"},
+		{Name: "Freelance", Type: "income"},
+		{Name: "Investimentos", Type: "income"},
+		{Name: "Outras receitas", Type: "income"},
+	},
+	"en-US": {
+		{Name: "Food", Type: "expense"},
+		{Name: "Transportation", Type: "expense"},
+		{Name: "Housing", Type: "expense"},
+		{Name: "Health", Type: "expense"},
+		{Name: "Entertainment", Type: "expense"},
+		{Name: "Education", Type: "expense"},
+		{Name: "Shopping", Type: "expense"},
+		{Name: "Bills & Utilities", Type: "expense"},
+		{Name: "Salary", Type: "income"},
+		{Name: "Freelance", Type: "income"},
+		{Name: "Investments", Type: "income"},
+		{Name: "Other income", Type: "income"},
+	},
+}
+
+// Lista as categorias modelo de cada idioma
+func (s *CategoryService) ListTemplates() []dto.CategoryTemplateResponse {
+	locales := []string{"pt-BR", "en-US"}
+
+	resp := make([]dto.CategoryTemplateResponse, 0, len(locales))
+	for _, locale := range locales {
+		resp = append(resp, dto.CategoryTemplateResponse{
+			Locale:     locale,
+			Categories: categoryTemplates[locale],
+		})
+	}
+
+	return resp
+}
+
+// Cria no workspace as categorias modelo do idioma (todas ou apenas as escolhidas).
+// Categorias com o mesmo nome de uma já existente são ignoradas, então reaplicar é seguro.
+func (s *CategoryService) ApplyTemplates(
+	userID, workspaceID uint,
+	input dto.CategoryTemplateInput,
+) (*dto.ApplyCategoryTemplatesResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := pickTemplates(input.Locale, input.Names)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := repository.FindAllCategoriesByWorkspace(s.DB, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, category := range existing {
+		taken[strings.ToLower(category.Name)] = true
+	}
+
+	resp := &dto.ApplyCategoryTemplatesResponse{
+		Created: []dto.CategoryResponse{},
+		Skipped: []string{},
+	}
+
+	var categories []models.Category
+	for _, template := range templates {
+		if taken[strings.ToLower(template.Name)] {
+			resp.Skipped = append(resp.Skipped, template.Name)
+			continue
+		}
+		categories = append(categories, newTemplateCategory(workspaceID, userID, template))
+	}
+
+	if len(categories) == 0 {
+		return resp, nil
+	}
+
+	if err := repository.CreateCategories(s.DB, categories); err != nil {
+		return nil, err
+	}
+
+	for _, category := range categories {
+		resp.Created = append(resp.Created, dto.CategoryResponse{
+			ID:       category.ID,
+			Name:     category.Name,
+			Type:     category.Type,
+			ParentID: category.ParentID,
+		})
+	}
+
+	// Invalida cache do workspace
+	s.cache.InvalidateWorkspaceCategories(workspaceID)
+
+	return resp, nil
+}
+
+// Cria as categorias modelo do idioma no workspace recém-criado (usado no cadastro)
+func seedDefaultCategories(db *gorm.DB, workspaceID, userID uint, locale string) error {
+	if locale == "" {
+		locale = DefaultCategoryLocale
+	}
+
+	templates, err := pickTemplates(locale, nil)
+	if err != nil {
+		return err
+	}
+
+	categories := make([]models.Category, 0, len(templates))
+	for _, template := range templates {
+		categories = append(categories, newTemplateCategory(workspaceID, userID, template))
+	}
+
+	return repository.CreateCategories(db, categories)
+}
+
+// Seleciona as categorias modelo do idioma. Sem nomes, retorna todas.
+func pickTemplates(locale string, names []string) ([]dto.CategoryTemplateItem, error) {
+	templates, ok := categoryTemplates[locale]
+	if !ok {
+		return nil, fmt.Errorf("%w: idioma %s", ErrCategoryTemplateNotFound, locale)
+	}
+
+	if len(names) == 0 {
+		return templates, nil
+	}
+
+	picked := make([]dto.CategoryTemplateItem, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		found := false
+		for _, template := range templates {
+			if strings.EqualFold(template.Name, name) {
+				if !seen[template.Name] {
+					picked = append(picked, template)
+					seen[template.Name] = true
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrCategoryTemplateNotFound, name)
+		}
+	}
+
+	return picked, nil
+}
+
+func newTemplateCategory(workspaceID, userID uint, template dto.CategoryTemplateItem) models.Category {
+	return models.Category{
+		WorkspaceID: workspaceID,
+		UserID:      &userID,
+		Name:        template.Name,
+		Type:        template.Type,
+	}
+}
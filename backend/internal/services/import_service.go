@@ -0,0 +1,270 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/importer"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Número máximo de linhas aceitas por importação
+const maxImportRows = 5000
+
+var ErrImportInvalidRows = errors.New("o arquivo contém linhas inválidas, corrija-as antes de importar")
+
+type ImportService struct {
+	DB    *gorm.DB
+	cache *cache.Cache
+}
+
+// Construtor
+func NewImportService(db *gorm.DB, cache *cache.Cache) *ImportService {
+	return &ImportService{DB: db, cache: cache}
+}
+
+// Importa transações de um CSV. Sem "commit" apenas retorna a pré-visualização.
+func (s *ImportService) ImportCSV(
+	userID, workspaceID uint,
+	input dto.CSVImportInput,
+	file io.Reader,
+) (*dto.ImportResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTargets(workspaceID, input.AccountID, input.DefaultCategoryID); err != nil {
+		return nil, err
+	}
+
+	mapping := importer.CSVMapping{
+		HasHeader:         input.HasHeader == nil || *input.HasHeader,
+		DateColumn:        input.DateColumn,
+		DateFormat:        input.DateFormat,
+		AmountColumn:      input.AmountColumn,
+		AmountSign:        input.AmountSign,
+		DecimalSeparator:  input.DecimalSeparator,
+		DescriptionColumn: input.DescriptionColumn,
+		CategoryColumn:    input.CategoryColumn,
+	}
+	if input.Delimiter != "" {
+		mapping.Delimiter = rune(input.Delimiter[0])
+	}
+
+	rows, err := importer.ParseCSV(file, mapping, maxImportRows)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, transactions, err := s.resolveRows(workspaceID, rows, input.DefaultCategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !input.Commit {
+		return resp, nil
+	}
+	if resp.InvalidRows > 0 {
+		return resp, ErrImportInvalidRows
+	}
+	if len(transactions) == 0 {
+		return nil, errors.New("nenhuma linha para importar")
+	}
+
+	if _, err := repository.CreateTransactionsBatch(s.DB, workspaceID, userID, input.AccountID, transactions); err != nil {
+		return nil, err
+	}
+
+	// Invalida cache de transações e contas do workspace
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
+
+	resp.Committed = true
+	return resp, nil
+}
+
+// Importa um extrato OFX. Linhas inválidas são rejeitadas e FITIDs já importados são ignorados.
+func (s *ImportService) ImportOFX(
+	userID, workspaceID uint,
+	input dto.OFXImportInput,
+	file io.Reader,
+) (*dto.OFXImportResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTargets(workspaceID, input.AccountID, input.DefaultCategoryID); err != nil {
+		return nil, err
+	}
+
+	rows, err := importer.ParseOFX(file, maxImportRows)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, transactions, err := s.resolveRows(workspaceID, rows, input.DefaultCategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// FITIDs repetidos dentro do próprio arquivo entram uma única vez
+	seen := make(map[string]bool, len(transactions))
+	unique := make([]models.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		if seen[*t.FITID] {
+			continue
+		}
+		seen[*t.FITID] = true
+		unique = append(unique, t)
+	}
+
+	created, err := repository.CreateTransactionsBatch(s.DB, workspaceID, userID, input.AccountID, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	createdFITIDs := make(map[string]bool, len(created))
+	for _, t := range created {
+		createdFITIDs[*t.FITID] = true
+	}
+
+	resp := &dto.OFXImportResponse{Rows: preview.Rows}
+	for i := range resp.Rows {
+		row := &resp.Rows[i]
+		switch {
+		case len(row.Errors) > 0:
+			row.Status = "rejected"
+			resp.Rejected++
+		case createdFITIDs[row.FITID]:
+			row.Status = "created"
+			resp.Created++
+			// Uma segunda ocorrência do mesmo FITID no arquivo é ignorada
+			delete(createdFITIDs, row.FITID)
+		default:
+			row.Status = "skipped"
+			resp.Skipped++
+		}
+	}
+
+	if resp.Created > 0 {
+		// Invalida cache de transações e contas do workspace
+		s.cache.InvalidateWorkspaceTransactions(workspaceID)
+		s.cache.InvalidateWorkspaceAccounts(workspaceID)
+	}
+
+	return resp, nil
+}
+
+// Confere se a conta de destino e a categoria padrão pertencem ao workspace
+func (s *ImportService) checkTargets(workspaceID, accountID, defaultCategoryID uint) error {
+	if _, err := repository.FindAccountByIDAndWorkspaceID(s.DB, workspaceID, accountID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("conta não encontrada")
+		}
+		return err
+	}
+
+	if defaultCategoryID != 0 {
+		if _, err := repository.FindCategoryByIDAndWorkspaceID(s.DB, workspaceID, defaultCategoryID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("categoria padrão não encontrada")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Associa as categorias pelo nome e monta a pré-visualização e as transações válidas
+func (s *ImportService) resolveRows(
+	workspaceID uint,
+	rows []importer.Row,
+	defaultCategoryID uint,
+) (*dto.ImportResponse, []models.Transaction, error) {
+	categories, err := repository.FindAllCategoriesByWorkspace(s.DB, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	categoryIDs := make(map[string]uint, len(categories))
+	categoriesByID := make(map[uint]*models.Category, len(categories))
+	for i, c := range categories {
+		categoryIDs[strings.ToLower(strings.TrimSpace(c.Name))] = c.ID
+		categoriesByID[c.ID] = &categories[i]
+	}
+
+	resp := &dto.ImportResponse{
+		TotalRows: len(rows),
+		Rows:      make([]dto.ImportRowResponse, 0, len(rows)),
+	}
+	transactions := make([]models.Transaction, 0, len(rows))
+
+	for _, row := range rows {
+		categoryID := defaultCategoryID
+		if row.Category != "" {
+			if id, ok := categoryIDs[strings.ToLower(row.Category)]; ok {
+				categoryID = id
+			} else if defaultCategoryID == 0 {
+				row.Errors = append(row.Errors, "categoria não encontrada: "+row.Category)
+			}
+		}
+		if categoryID == 0 && row.Category == "" {
+			row.Errors = append(row.Errors, "categoria não informada")
+		}
+		if category, ok := categoriesByID[categoryID]; ok && row.Type != "" {
+			if err := checkCategoryUsage(category, row.Type, false); err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+		}
+
+		item := dto.ImportRowResponse{
+			Line:        row.Line,
+			Description: row.Description,
+			Type:        row.Type,
+			Amount:      row.Amount,
+			CategoryID:  categoryID,
+			FITID:       row.FITID,
+			Errors:      row.Errors,
+		}
+		if !row.Date.IsZero() {
+			item.Date = row.Date.Format("2006-01-02")
+		}
+		resp.Rows = append(resp.Rows, item)
+
+		if len(row.Errors) > 0 {
+			resp.InvalidRows++
+			continue
+		}
+
+		resp.ValidRows++
+		if row.Type == "income" {
+			resp.TotalIncome += row.Amount
+		} else {
+			resp.TotalExpense += row.Amount
+		}
+
+		transaction := models.Transaction{
+			CategoryID:  categoryID,
+			Type:        row.Type,
+			Amount:      row.Amount,
+			Description: row.Description,
+			Date:        row.Date,
+		}
+		if row.FITID != "" {
+			fitid := row.FITID
+			transaction.FITID = &fitid
+		}
+		transactions = append(transactions, transaction)
+	}
+	resp.Net = resp.TotalIncome - resp.TotalExpense
+
+	return resp, transactions, nil
+}
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// Ocorrências geradas por regra a cada execução; o restante fica para as próximas
+	maxOccurrencesPerRun = 100
+	// Quanto a data inicial pode estar no passado (limita a geração retroativa)
+	maxRecurringBackfillDays = 366
+)
+
+var (
+	ErrRecurringStartTooOld   = fmt.Errorf("a data inicial não pode ser anterior a %d dias atrás", maxRecurringBackfillDays)
+	ErrRecurringAuthorDeleted = errors.New("o autor da regra excluiu a conta")
+)
+
+type RecurringService struct {
+	DB           *gorm.DB
+	transactions *TransactionService
+}
+
+// Construtor
+func NewRecurringService(db *gorm.DB, transactions *TransactionService) *RecurringService {
+	return &RecurringService{DB: db, transactions: transactions}
+}
+
+// Cria uma regra recorrente e já gera as ocorrências vencidas
+func (s *RecurringService) CreateRecurring(
+	userID, workspaceID uint,
+	input dto.RecurringTransactionInput,
+) (*models.RecurringTransaction, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := buildRecurringRule(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRecurringStart(rule.StartDate); err != nil {
+		return nil, err
+	}
+	if err := s.checkTargets(workspaceID, rule); err != nil {
+		return nil, err
+	}
+	rule.WorkspaceID = workspaceID
+	rule.UserID = &userID
+	rule.NextRunDate = nextRunDate(rule)
+
+	if err := repository.CreateRecurringTransaction(s.DB, rule); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.processRule(rule, dateOnly(time.Now())); err != nil {
+		log.Printf("Erro ao gerar ocorrências da recorrência %d: %v", rule.ID, err)
+	}
+
+	return rule, nil
+}
+
+// Lista as regras recorrentes do workspace
+func (s *RecurringService) ListRecurring(userID, workspaceID uint) ([]models.RecurringTransaction, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindRecurringTransactionsByWorkspace(s.DB, workspaceID)
+}
+
+// Recupera uma regra recorrente
+func (s *RecurringService) RetrieveRecurring(userID, workspaceID, id uint) (*models.RecurringTransaction, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindRecurringTransactionByIDAndWorkspaceID(s.DB, workspaceID, id)
+}
+
+// Atualiza uma regra recorrente, mantendo as ocorrências já geradas
+func (s *RecurringService) UpdateRecurring(
+	userID, workspaceID, id uint,
+	input dto.RecurringTransactionInput,
+) (*models.RecurringTransaction, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := repository.FindRecurringTransactionByIDAndWorkspaceID(s.DB, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := buildRecurringRule(input)
+	if err != nil {
+		return nil, err
+	}
+	// Regras antigas podem ser editadas; só uma nova data inicial passa pelo limite
+	if !rule.StartDate.Equal(current.StartDate) {
+		if err := checkRecurringStart(rule.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkTargets(workspaceID, rule); err != nil {
+		return nil, err
+	}
+	rule.ID = id
+	rule.WorkspaceID = workspaceID
+	rule.UserID = current.UserID
+	if rule.UserID == nil {
+		// O autor excluiu a conta: quem edita passa a gerar as ocorrências
+		rule.UserID = &userID
+	}
+	rule.Occurrences = current.Occurrences
+	// Com outro calendário, a contagem passa a ser a das datas novas que caem até a última
+	// ocorrência já gerada: a próxima é a primeira data nova depois dela
+	if recurringScheduleChanged(current, rule) {
+		last, err := repository.FindLastRecurringOccurrence(s.DB, id)
+		if err != nil {
+			return nil, err
+		}
+		rule.Occurrences = 0
+		if last != nil {
+			rule.Occurrences = occurrencesThrough(rule, *last)
+		}
+	}
+	rule.CreatedAt = current.CreatedAt
+	// last_error e failed_at ficam vazios: a edição limpa a última falha registrada
+	rule.NextRunDate = nextRunDate(rule)
+
+	if err := repository.UpdateRecurringTransaction(s.DB, rule); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.processRule(rule, dateOnly(time.Now())); err != nil {
+		log.Printf("Erro ao gerar ocorrências da recorrência %d: %v", rule.ID, err)
+	}
+
+	return rule, nil
+}
+
+// Deleta uma regra recorrente (as transações já geradas são mantidas)
+func (s *RecurringService) DeleteRecurring(userID, workspaceID, id uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	return repository.DeleteRecurringTransaction(s.DB, id, workspaceID)
+}
+
+// Confere se a conta e a categoria da regra pertencem ao workspace e se a
+// categoria aceita o tipo da regra
+func (s *RecurringService) checkTargets(workspaceID uint, rule *models.RecurringTransaction) error {
+	if _, err := repository.FindAccountByIDAndWorkspaceID(s.DB, workspaceID, rule.AccountID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("conta não encontrada")
+		}
+		return err
+	}
+
+	category, err := checkCategory(s.DB, workspaceID, rule.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	return checkCategoryUsage(category, rule.Type, false)
+}
+
+// Gera todas as ocorrências vencidas até a data de "until" (inclusive) e retorna quantas transações foram criadas.
+// Pode ser chamado várias vezes: ocorrências já geradas são ignoradas.
+func (s *RecurringService) ProcessDue(until time.Time) (int, error) {
+	until = dateOnly(until)
+
+	rules, err := repository.FindDueRecurringTransactions(s.DB, until)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for i := range rules {
+		n, err := s.processRule(&rules[i], until)
+		created += n
+		if err != nil {
+			log.Printf("Erro ao gerar ocorrências da recorrência %d: %v", rules[i].ID, err)
+		}
+	}
+
+	return created, nil
+}
+
+// Materializa as ocorrências pendentes de uma regra, uma a uma, via TransactionService.
+// Uma ocorrência que não pode ser gerada é registrada na regra (last_error/failed_at) e
+// pulada; se a falha não vai se resolver sozinha (sem permissão no workspace, autor que
+// excluiu a conta, categoria arquivada ou de outro tipo), a regra é desativada. Falhas de conexão com o banco
+// interrompem o processamento, que é retomado na próxima execução.
+func (s *RecurringService) processRule(rule *models.RecurringTransaction, until time.Time) (int, error) {
+	created := 0
+
+	for i := 0; i < maxOccurrencesPerRun && rule.Active && rule.NextRunDate != nil && !rule.NextRunDate.After(until); i++ {
+		date := *rule.NextRunDate
+
+		// A ocorrência pode já existir se o processo caiu antes de avançar a regra
+		exists, err := repository.RecurringOccurrenceExists(s.DB, rule.ID, date)
+		if err != nil {
+			return created, err
+		}
+
+		if !exists {
+			err := ErrRecurringAuthorDeleted
+			if rule.UserID != nil {
+				recurringID := rule.ID
+				_, err = s.transactions.CreateTransaction(*rule.UserID, rule.WorkspaceID, dto.TransactionInput{
+					AccountID:   rule.AccountID,
+					CategoryID:  rule.CategoryID,
+					Type:        rule.Type,
+					Amount:      rule.Amount,
+					Description: rule.Description,
+					Date:        date.Format("2006-01-02"),
+					RecurringID: &recurringID,
+				})
+			}
+			if err != nil {
+				if isTransientError(err) {
+					return created, fmt.Errorf("ocorrência de %s: %w", date.Format("2006-01-02"), err)
+				}
+
+				now := time.Now()
+				rule.LastError = truncateString(fmt.Sprintf("ocorrência de %s: %v", date.Format("2006-01-02"), err), 255)
+				rule.FailedAt = &now
+				rule.Active = !isPermanentRecurringError(err)
+				log.Printf("Recorrência %d: %s (ativa: %t)", rule.ID, rule.LastError, rule.Active)
+			} else {
+				created++
+			}
+		}
+
+		// Desativada, a regra mantém a ocorrência pendente para quando for reativada
+		expected := rule.Occurrences
+		if rule.Active {
+			rule.Occurrences++
+			rule.NextRunDate = nextRunDate(rule)
+		}
+
+		advanced, err := repository.AdvanceRecurringTransaction(s.DB, rule, expected)
+		if err != nil {
+			return created, err
+		}
+		if !advanced {
+			// Outra execução já avançou esta regra
+			break
+		}
+	}
+
+	return created, nil
+}
+
+// Falhas que impedem a regra de gerar qualquer ocorrência até ser corrigida
+func isPermanentRecurringError(err error) bool {
+	return errors.Is(err, ErrWorkspaceNotFound) ||
+		errors.Is(err, ErrWorkspaceForbidden) ||
+		errors.Is(err, ErrRecurringAuthorDeleted) ||
+		errors.Is(err, ErrCategoryArchived) ||
+		errors.Is(err, ErrCategoryTypeMismatch)
+}
+
+// Falhas de conexão ou tempo esgotado: a ocorrência é tentada de novo na próxima execução
+func isTransientError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled) ||
+		errors.As(err, &netErr)
+}
+
+// Limita a data inicial no passado, evitando gerar anos de ocorrências retroativas
+func checkRecurringStart(start time.Time) error {
+	if start.Before(dateOnly(time.Now()).AddDate(0, 0, -maxRecurringBackfillDays)) {
+		return ErrRecurringStartTooOld
+	}
+	return nil
+}
+
+// Monta a regra a partir do input, validando as datas
+func buildRecurringRule(input dto.RecurringTransactionInput) (*models.RecurringTransaction, error) {
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, errors.New("data inicial inválida")
+	}
+
+	var endDate *time.Time
+	if input.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", input.EndDate)
+		if err != nil {
+			return nil, errors.New("data final inválida")
+		}
+		if parsed.Before(startDate) {
+			return nil, errors.New("data final anterior à data inicial")
+		}
+		endDate = &parsed
+	}
+
+	interval := input.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	// Dia do mês só faz sentido em recorrências mensais e anuais
+	dayOfMonth := input.DayOfMonth
+	if input.Frequency != "monthly" && input.Frequency != "yearly" {
+		dayOfMonth = nil
+	}
+
+	active := true
+	if input.Active != nil {
+		active = *input.Active
+	}
+
+	return &models.RecurringTransaction{
+		AccountID:   input.AccountID,
+		CategoryID:  input.CategoryID,
+		Type:        input.Type,
+		Amount:      input.Amount,
+		Description: input.Description,
+		Frequency:   input.Frequency,
+		Interval:    interval,
+		DayOfMonth:  dayOfMonth,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Count:       input.Count,
+		Active:      active,
+	}, nil
+}
+
+// Checa se a edição mudou as datas das ocorrências
+func recurringScheduleChanged(current, rule *models.RecurringTransaction) bool {
+	sameDay := (current.DayOfMonth == nil && rule.DayOfMonth == nil) ||
+		(current.DayOfMonth != nil && rule.DayOfMonth != nil && *current.DayOfMonth == *rule.DayOfMonth)
+
+	return !current.StartDate.Equal(rule.StartDate) ||
+		current.Frequency != rule.Frequency ||
+		current.Interval != rule.Interval ||
+		!sameDay
+}
+
+// Quantas ocorrências da regra caem até a data (inclusive)
+func occurrencesThrough(rule *models.RecurringTransaction, date time.Time) int {
+	n := 0
+	for !occurrenceDate(rule, n).After(date) {
+		n++
+	}
+	return n
+}
+
+// Próxima data a ser gerada, ou nil se a regra terminou (por data final ou quantidade)
+func nextRunDate(rule *models.RecurringTransaction) *time.Time {
+	if rule.Count != nil && rule.Occurrences >= *rule.Count {
+		return nil
+	}
+
+	date := occurrenceDate(rule, rule.Occurrences)
+	if rule.EndDate != nil && date.After(*rule.EndDate) {
+		return nil
+	}
+
+	return &date
+}
+
+// Data da n-ésima ocorrência (começando em 0), calculada sempre a partir da data inicial
+// para não acumular deslocamentos (ex: dia 31 em meses mais curtos)
+func occurrenceDate(rule *models.RecurringTransaction, n int) time.Time {
+	start := rule.StartDate
+	step := n * rule.Interval
+
+	switch rule.Frequency {
+	case "daily":
+		return start.AddDate(0, 0, step)
+	case "weekly":
+		return start.AddDate(0, 0, 7*step)
+	}
+
+	day := start.Day()
+	if rule.DayOfMonth != nil {
+		day = *rule.DayOfMonth
+	}
+
+	if rule.Frequency == "yearly" {
+		date := clampedDate(start.Year()+step, start.Month(), day)
+		if clampedDate(start.Year(), start.Month(), day).Before(start) {
+			date = clampedDate(start.Year()+step+rule.Interval, start.Month(), day)
+		}
+		return date
+	}
+
+	// monthly: se o dia escolhido já passou no mês inicial, começa no próximo período
+	if clampedDate(start.Year(), start.Month(), day).Before(start) {
+		step += rule.Interval
+	}
+	return clampedDate(start.Year(), start.Month()+time.Month(step), day)
+}
+
+// Monta a data limitando o dia ao último dia do mês
+func clampedDate(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Remove o horário, no mesmo formato das colunas DATE
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
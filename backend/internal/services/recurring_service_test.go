@@ -0,0 +1,165 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+)
+
+func mustDate(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestOccurrenceDate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.RecurringTransaction
+		want []string
+	}{
+		{
+			name: "mensal no dia 31 limita ao fim do mês",
+			rule: models.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: mustDate("2025-01-31")},
+			want: []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"},
+		},
+		{
+			name: "mensal em ano bissexto",
+			rule: models.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: mustDate("2024-01-31")},
+			want: []string{"2024-01-31", "2024-02-29", "2024-03-31"},
+		},
+		{
+			name: "dia do mês 31 começando no meio de fevereiro",
+			rule: models.RecurringTransaction{Frequency: "monthly", Interval: 1, DayOfMonth: intPtr(31), StartDate: mustDate("2025-02-10")},
+			want: []string{"2025-02-28", "2025-03-31", "2025-04-30"},
+		},
+		{
+			name: "dia do mês já passado começa no mês seguinte",
+			rule: models.RecurringTransaction{Frequency: "monthly", Interval: 1, DayOfMonth: intPtr(5), StartDate: mustDate("2025-02-10")},
+			want: []string{"2025-03-05", "2025-04-05"},
+		},
+		{
+			name: "bimestral atravessando o ano",
+			rule: models.RecurringTransaction{Frequency: "monthly", Interval: 2, StartDate: mustDate("2025-11-30")},
+			want: []string{"2025-11-30", "2026-01-30", "2026-03-30"},
+		},
+		{
+			name: "anual em 29 de fevereiro",
+			rule: models.RecurringTransaction{Frequency: "yearly", Interval: 1, StartDate: mustDate("2024-02-29")},
+			want: []string{"2024-02-29", "2025-02-28", "2026-02-28", "2027-02-28", "2028-02-29"},
+		},
+		{
+			name: "quinzenal",
+			rule: models.RecurringTransaction{Frequency: "weekly", Interval: 2, StartDate: mustDate("2025-12-24")},
+			want: []string{"2025-12-24", "2026-01-07", "2026-01-21"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for n, want := range tt.want {
+				if got := occurrenceDate(&tt.rule, n).Format("2006-01-02"); got != want {
+					t.Errorf("ocorrência %d = %s, esperado %s", n, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNextRunDateEnds(t *testing.T) {
+	end := mustDate("2025-03-15")
+	rule := models.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: mustDate("2025-01-31"), EndDate: &end}
+
+	rule.Occurrences = 1
+	if got := nextRunDate(&rule); got == nil || got.Format("2006-01-02") != "2025-02-28" {
+		t.Fatalf("próxima data = %v, esperado 2025-02-28", got)
+	}
+	rule.Occurrences = 2
+	if got := nextRunDate(&rule); got != nil {
+		t.Errorf("próxima data depois da data final = %v, esperado nil", got)
+	}
+
+	rule = models.RecurringTransaction{Frequency: "daily", Interval: 1, StartDate: mustDate("2025-01-01"), Count: intPtr(3)}
+	rule.Occurrences = 2
+	if got := nextRunDate(&rule); got == nil || got.Format("2006-01-02") != "2025-01-03" {
+		t.Fatalf("próxima data = %v, esperado 2025-01-03", got)
+	}
+	rule.Occurrences = 3
+	if got := nextRunDate(&rule); got != nil {
+		t.Errorf("próxima data depois do limite de ocorrências = %v, esperado nil", got)
+	}
+}
+
+func TestOccurrencesAfterScheduleChange(t *testing.T) {
+	// Regra mensal no dia 10 que já gerou janeiro, fevereiro e março
+	current := models.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: mustDate("2026-01-10"), Occurrences: 3}
+	last := mustDate("2026-03-10")
+
+	tests := []struct {
+		name    string
+		rule    models.RecurringTransaction
+		changed bool
+		want    int
+		next    string
+	}{
+		{
+			name:    "sem mudança no calendário",
+			rule:    models.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: mustDate("2026-01-10")},
+			changed: false,
+		},
+		{
+			name:    "novo dia do mês continua depois da última ocorrência",
+			rule:    models.RecurringTransaction{Frequency: "monthly", Interval: 1, DayOfMonth: intPtr(20), StartDate: mustDate("2026-01-10")},
+			changed: true,
+			want:    2,
+			next:    "2026-03-20",
+		},
+		{
+			name:    "dia do mês anterior pula para o mês seguinte",
+			rule:    models.RecurringTransaction{Frequency: "monthly", Interval: 1, DayOfMonth: intPtr(5), StartDate: mustDate("2026-01-10")},
+			changed: true,
+			want:    2,
+			next:    "2026-04-05",
+		},
+		{
+			name:    "nova frequência",
+			rule:    models.RecurringTransaction{Frequency: "weekly", Interval: 1, StartDate: mustDate("2026-03-01")},
+			changed: true,
+			want:    2,
+			next:    "2026-03-15",
+		},
+		{
+			name:    "nova data inicial depois da última ocorrência",
+			rule:    models.RecurringTransaction{Frequency: "monthly", Interval: 1, StartDate: mustDate("2026-05-01")},
+			changed: true,
+			want:    0,
+			next:    "2026-05-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recurringScheduleChanged(&current, &tt.rule); got != tt.changed {
+				t.Fatalf("recurringScheduleChanged = %t, esperado %t", got, tt.changed)
+			}
+			if !tt.changed {
+				return
+			}
+
+			tt.rule.Occurrences = occurrencesThrough(&tt.rule, last)
+			if tt.rule.Occurrences != tt.want {
+				t.Errorf("ocorrências = %d, esperado %d", tt.rule.Occurrences, tt.want)
+			}
+			if got := nextRunDate(&tt.rule); got == nil || got.Format("2006-01-02") != tt.next {
+				t.Errorf("próxima data = %v, esperado %s", got, tt.next)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Máximo de períodos na série do resumo (ex: 366 dias com group_by=day)
+const maxReportPeriods = 400
+
+var ErrReportRangeTooLarge = fmt.Errorf("intervalo grande demais: a série pode ter no máximo %d períodos, use um group_by maior", maxReportPeriods)
+
+type ReportService struct {
+	DB    *gorm.DB
+	cache *cache.Cache
+}
+
+// Construtor
+func NewReportService(db *gorm.DB, cache *cache.Cache) *ReportService {
+	return &ReportService{DB: db, cache: cache}
+}
+
+// Gera o resumo de receitas x despesas agrupado por período (e opcionalmente por categoria ou tipo)
+func (s *ReportService) Summary(
+	userID, workspaceID uint,
+	filter dto.TransactionFilter,
+	groupBy, splitBy string,
+) (*dto.ReportSummaryResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupBy == "" {
+		groupBy = "month"
+	}
+	if groupBy != "day" && groupBy != "week" && groupBy != "month" && groupBy != "year" {
+		return nil, errors.New("group_by inválido, use day, week, month ou year")
+	}
+	if splitBy != "" && splitBy != "category" && splitBy != "subcategory" && splitBy != "type" {
+		return nil, errors.New("split_by inválido, use category, subcategory ou type")
+	}
+
+	// Monta a chave do cache
+	cacheKey := fmt.Sprintf(
+		"reports:%d:summary:%s:group=%s:split=%s",
+		workspaceID,
+		transactionFilterKey(filter),
+		groupBy,
+		splitBy,
+	)
+
+	// Verifica se existe no cache
+	var cached dto.ReportSummaryResponse
+	found, err := s.cache.Get(cacheKey, &cached)
+	if err == nil && found {
+		fmt.Println("Pegando do cache:", cacheKey)
+		return &cached, nil
+	}
+
+	// "category" soma as subcategorias na categoria raiz; "subcategory" detalha cada categoria
+	byCategory := splitBy == "category" || splitBy == "subcategory"
+	rows, err := repository.SummarizeTransactions(s.DB, workspaceID, filter, groupBy, byCategory, splitBy == "category")
+	if err != nil {
+		return nil, err
+	}
+
+	// A série é preenchida período a período: limita o intervalo antes de montá-la
+	if len(rows) > 0 || (filter.FromDate != nil && filter.ToDate != nil) {
+		first, last := periodBounds(rows, filter, groupBy)
+		if countPeriods(first, last, groupBy, maxReportPeriods+1) > maxReportPeriods {
+			return nil, ErrReportRangeTooLarge
+		}
+	}
+
+	resp := buildSummary(rows, filter, groupBy, splitBy)
+
+	// Salva no cache
+	if err := s.cache.Set(cacheKey, resp, time.Minute*5); err != nil {
+		fmt.Println("Erro ao salvar no cache:", err)
+	}
+
+	return resp, nil
+}
+
+// Soma receitas e despesas de cada etiqueta. Aceita os mesmos filtros da listagem
+// de transações; uma transação com várias etiquetas entra no total de cada uma.
+func (s *ReportService) TagTotals(
+	userID, workspaceID uint,
+	filter dto.TransactionFilter,
+) (*dto.TagReportResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Monta a chave do cache
+	cacheKey := fmt.Sprintf("reports:%d:tags:%s", workspaceID, transactionFilterKey(filter))
+
+	// Verifica se existe no cache
+	var cached dto.TagReportResponse
+	found, err := s.cache.Get(cacheKey, &cached)
+	if err == nil && found {
+		fmt.Println("Pegando do cache:", cacheKey)
+		return &cached, nil
+	}
+
+	rows, err := repository.SumTransactionsByTag(s.DB, workspaceID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// As linhas vêm ordenadas por etiqueta, uma por tipo
+	resp := &dto.TagReportResponse{Tags: []dto.TagReportItem{}}
+	for _, row := range rows {
+		if n := len(resp.Tags); n == 0 || resp.Tags[n-1].TagID != row.TagID {
+			resp.Tags = append(resp.Tags, dto.TagReportItem{TagID: row.TagID, Name: row.TagName})
+		}
+		item := &resp.Tags[len(resp.Tags)-1]
+		if row.Type == "income" {
+			item.Income += row.Total
+		} else {
+			item.Expense += row.Total
+		}
+		item.Count += row.Count
+		item.Net = item.Income - item.Expense
+	}
+
+	// Salva no cache
+	if err := s.cache.Set(cacheKey, resp, time.Minute*5); err != nil {
+		fmt.Println("Erro ao salvar no cache:", err)
+	}
+
+	return resp, nil
+}
+
+// Converte as linhas agregadas em série temporal (sem lacunas) e totais
+func buildSummary(
+	rows []repository.SummaryRow,
+	filter dto.TransactionFilter,
+	groupBy, splitBy string,
+) *dto.ReportSummaryResponse {
+	resp := &dto.ReportSummaryResponse{
+		GroupBy: groupBy,
+		SplitBy: splitBy,
+		Series:  []dto.ReportPeriod{},
+	}
+
+	periods := make(map[string]*dto.ReportPeriod)
+	breakdowns := make(map[string]map[string]*dto.ReportBreakdownItem)
+	totals := make(map[string]*dto.ReportBreakdownItem)
+
+	for _, row := range rows {
+		key := row.Period.Format("2006-01-02")
+		period, ok := periods[key]
+		if !ok {
+			period = &dto.ReportPeriod{Period: key}
+			periods[key] = period
+			breakdowns[key] = make(map[string]*dto.ReportBreakdownItem)
+		}
+
+		addToPeriod(&period.Income, &period.Expense, &period.Count, row)
+		addToPeriod(&resp.Totals.Income, &resp.Totals.Expense, &resp.Totals.Count, row)
+
+		if splitBy == "" {
+			continue
+		}
+
+		itemKey, label := row.Type, row.Type
+		if splitBy == "category" || splitBy == "subcategory" {
+			itemKey, label = strconv.FormatUint(uint64(row.CategoryID), 10), row.CategoryName
+		}
+
+		item, ok := breakdowns[key][itemKey]
+		if !ok {
+			item = &dto.ReportBreakdownItem{Key: itemKey, Label: label}
+			breakdowns[key][itemKey] = item
+		}
+		addToPeriod(&item.Income, &item.Expense, &item.Count, row)
+
+		total, ok := totals[itemKey]
+		if !ok {
+			total = &dto.ReportBreakdownItem{Key: itemKey, Label: label}
+			totals[itemKey] = total
+		}
+		addToPeriod(&total.Income, &total.Expense, &total.Count, row)
+	}
+
+	// Preenche os períodos sem transações com zero
+	if len(rows) > 0 || (filter.FromDate != nil && filter.ToDate != nil) {
+		first, last := periodBounds(rows, filter, groupBy)
+		for p := first; !p.After(last); p = nextPeriod(p, groupBy) {
+			key := p.Format("2006-01-02")
+			period, ok := periods[key]
+			if !ok {
+				period = &dto.ReportPeriod{Period: key}
+			}
+			period.Net = period.Income - period.Expense
+			period.Breakdown = sortedBreakdown(breakdowns[key])
+			resp.Series = append(resp.Series, *period)
+		}
+	}
+
+	resp.Totals.Net = resp.Totals.Income - resp.Totals.Expense
+	resp.Totals.Breakdown = sortedBreakdown(totals)
+
+	return resp
+}
+
+// Soma a linha em receita ou despesa
+func addToPeriod(income, expense *money.Amount, count *int, row repository.SummaryRow) {
+	if row.Type == "income" {
+		*income += row.Total
+	} else {
+		*expense += row.Total
+	}
+	*count += row.Count
+}
+
+// Primeiro e último período da série: usa o intervalo do filtro quando informado
+func periodBounds(rows []repository.SummaryRow, filter dto.TransactionFilter, groupBy string) (time.Time, time.Time) {
+	var first, last time.Time
+	if len(rows) > 0 {
+		first, last = rows[0].Period, rows[len(rows)-1].Period
+	}
+	if filter.FromDate != nil {
+		first = truncatePeriod(*filter.FromDate, groupBy)
+	}
+	if filter.ToDate != nil {
+		last = truncatePeriod(*filter.ToDate, groupBy)
+	}
+	return truncatePeriod(first, groupBy), truncatePeriod(last, groupBy)
+}
+
+// Equivalente em Go ao date_trunc do Postgres (semanas começam na segunda-feira)
+func truncatePeriod(t time.Time, groupBy string) time.Time {
+	day := dateOnly(t)
+	switch groupBy {
+	case "week":
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case "month":
+		return firstOfMonth(day)
+	case "year":
+		return time.Date(day.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// Início do período seguinte
+func nextPeriod(t time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case "week":
+		return t.AddDate(0, 0, 7)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	case "year":
+		return t.AddDate(1, 0, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// Conta os períodos entre first e last (inclusive), parando ao atingir limit
+func countPeriods(first, last time.Time, groupBy string, limit int) int {
+	n := 0
+	for p := first; !p.After(last) && n < limit; p = nextPeriod(p, groupBy) {
+		n++
+	}
+	return n
+}
+
+// Ordena o detalhamento pelo maior volume movimentado
+func sortedBreakdown(items map[string]*dto.ReportBreakdownItem) []dto.ReportBreakdownItem {
+	if len(items) == 0 {
+		return nil
+	}
+
+	result := make([]dto.ReportBreakdownItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		vi, vj := result[i].Income+result[i].Expense, result[j].Income+result[j].Expense
+		if vi != vj {
+			return vi > vj
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrSessionRevoked = errors.New("sessão encerrada")
+
+type SessionService struct {
+	DB *gorm.DB
+}
+
+// Construtor
+func NewSessionService(db *gorm.DB) *SessionService {
+	return &SessionService{DB: db}
+}
+
+// Lista as sessões ativas do usuário
+func (s *SessionService) ListSessions(userID uint) ([]models.Session, error) {
+	return repository.FindActiveSessionsByUser(s.DB, userID)
+}
+
+// Encerra uma sessão (dispositivo) do usuário
+func (s *SessionService) RevokeSession(userID, sessionID uint) error {
+	return repository.RevokeSession(s.DB, sessionID, userID, time.Now())
+}
+
+// Confere se a sessão do access token continua ativa. Usado pelo AuthMiddleware.
+func (s *SessionService) ValidateSession(sessionID, userID uint) error {
+	active, err := repository.IsSessionActive(s.DB, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrSessionRevoked
+	}
+
+	// Falha ao registrar o acesso não deve bloquear a requisição
+	if err := repository.TouchSession(s.DB, sessionID, time.Now()); err != nil {
+		log.Printf("Erro ao atualizar último acesso da sessão %d: %v", sessionID, err)
+	}
+
+	return nil
+}
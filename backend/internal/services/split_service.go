@@ -0,0 +1,525 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Formas de dividir uma despesa
+const (
+	SplitEqual   = "equal"   // Partes iguais; sobras de centavos vão para os primeiros participantes
+	SplitExact   = "exact"   // Valores informados, que precisam somar o valor da transação
+	SplitPercent = "percent" // Percentuais que precisam somar 100
+	SplitShares  = "shares"  // Pesos proporcionais (ex: 2 para um casal, 1 para os demais)
+)
+
+var (
+	ErrParticipantNotFound = errors.New("participante não encontrado")
+	ErrParticipantInUse    = errors.New("participante possui divisões ou pagamentos registrados")
+	ErrParticipantExists   = errors.New("o usuário já possui um participante neste workspace")
+)
+
+type SplitService struct {
+	DB *gorm.DB
+}
+
+// Construtor
+func NewSplitService(db *gorm.DB) *SplitService {
+	return &SplitService{DB: db}
+}
+
+// Cria um participante. Se vinculado a um usuário, ele precisa ser membro do workspace.
+func (s *SplitService) CreateParticipant(
+	userID, workspaceID uint,
+	input dto.ParticipantInput,
+) (*models.Participant, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.UserID != nil {
+		if _, err := repository.FindWorkspaceMember(s.DB, workspaceID, *input.UserID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("usuário não é membro do workspace")
+			}
+			return nil, err
+		}
+
+		if _, err := repository.FindParticipantByUser(s.DB, workspaceID, *input.UserID); err == nil {
+			return nil, ErrParticipantExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	participant := &models.Participant{
+		WorkspaceID: workspaceID,
+		UserID:      input.UserID,
+		Name:        input.Name,
+	}
+	if err := repository.CreateParticipant(s.DB, participant); err != nil {
+		return nil, err
+	}
+
+	return participant, nil
+}
+
+// Lista os participantes do workspace
+func (s *SplitService) ListParticipants(userID, workspaceID uint) ([]models.Participant, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindParticipantsByWorkspace(s.DB, workspaceID)
+}
+
+// Deleta um participante que não tenha divisões nem pagamentos
+func (s *SplitService) DeleteParticipant(userID, workspaceID, participantID uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := repository.ParticipantInUse(s.DB, workspaceID, participantID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrParticipantInUse
+	}
+
+	return repository.DeleteParticipant(s.DB, participantID, workspaceID)
+}
+
+// Registra um pagamento entre participantes, quitando (total ou parcialmente) a dívida
+func (s *SplitService) CreateSettlement(
+	userID, workspaceID uint,
+	input dto.SettlementInput,
+) (*models.Settlement, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedDate, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return nil, errors.New("data inválida")
+	}
+
+	participants, err := repository.FindParticipantsByIDs(
+		s.DB, workspaceID, []uint{input.FromParticipantID, input.ToParticipantID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(participants) != 2 {
+		return nil, ErrParticipantNotFound
+	}
+
+	settlement := &models.Settlement{
+		WorkspaceID:       workspaceID,
+		UserID:            &userID,
+		FromParticipantID: input.FromParticipantID,
+		ToParticipantID:   input.ToParticipantID,
+		Amount:            input.Amount,
+		Date:              parsedDate,
+		Note:              input.Note,
+	}
+	if err := repository.CreateSettlement(s.DB, settlement); err != nil {
+		return nil, err
+	}
+
+	for _, p := range participants {
+		if p.ID == settlement.FromParticipantID {
+			settlement.FromParticipant = p
+		} else {
+			settlement.ToParticipant = p
+		}
+	}
+
+	return settlement, nil
+}
+
+// Lista os pagamentos entre participantes
+func (s *SplitService) ListSettlements(userID, workspaceID uint, page, limit int) ([]models.Settlement, int, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return repository.FindSettlementsByWorkspace(s.DB, workspaceID, page, limit)
+}
+
+// Deleta um pagamento, reabrindo a dívida correspondente
+func (s *SplitService) DeleteSettlement(userID, workspaceID, settlementID uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	return repository.DeleteSettlement(s.DB, settlementID, workspaceID)
+}
+
+// Calcula quem deve a quem: dívidas líquidas entre cada par de participantes e o saldo de cada um
+func (s *SplitService) Ledger(userID, workspaceID uint) (*dto.SplitLedgerResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	names, owed, err := s.loadDebts(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.SplitLedgerResponse{
+		Balances: []dto.ParticipantBalanceResponse{},
+		Debts:    []dto.DebtResponse{},
+	}
+
+	// Compensa as dívidas nos dois sentidos de cada par
+	balances := make(map[uint]money.Amount)
+	pairs := make(map[[2]uint]bool)
+	for pair, amount := range owed {
+		balances[pair[0]] -= amount
+		balances[pair[1]] += amount
+		pairs[[2]uint{min(pair[0], pair[1]), max(pair[0], pair[1])}] = true
+	}
+	for pair := range pairs {
+		a, b := pair[0], pair[1]
+		net := owed[[2]uint{a, b}] - owed[[2]uint{b, a}]
+		if net > 0 {
+			resp.Debts = append(resp.Debts, debtResponse(names, a, b, net))
+		} else if net < 0 {
+			resp.Debts = append(resp.Debts, debtResponse(names, b, a, -net))
+		}
+	}
+
+	for id, balance := range balances {
+		if balance == 0 {
+			continue
+		}
+		resp.Balances = append(resp.Balances, dto.ParticipantBalanceResponse{
+			ParticipantID: id,
+			Name:          names[id],
+			Balance:       balance,
+		})
+	}
+
+	sort.Slice(resp.Debts, func(i, j int) bool {
+		if resp.Debts[i].FromParticipantID != resp.Debts[j].FromParticipantID {
+			return resp.Debts[i].FromParticipantID < resp.Debts[j].FromParticipantID
+		}
+		return resp.Debts[i].ToParticipantID < resp.Debts[j].ToParticipantID
+	})
+	sort.Slice(resp.Balances, func(i, j int) bool {
+		return resp.Balances[i].ParticipantID < resp.Balances[j].ParticipantID
+	})
+
+	return resp, nil
+}
+
+// Simplifica as dívidas: a partir do saldo de cada participante, sugere o menor
+// conjunto de pagamentos (no máximo n-1) que zera todos os saldos
+func (s *SplitService) SimplifyDebts(userID, workspaceID uint) (*dto.SimplifiedDebtsResponse, error) {
+	ledger, err := s.Ledger(userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var creditors, debtors []dto.ParticipantBalanceResponse
+	for _, b := range ledger.Balances {
+		if b.Balance > 0 {
+			creditors = append(creditors, b)
+		} else {
+			b.Balance = -b.Balance
+			debtors = append(debtors, b)
+		}
+	}
+
+	// Maiores valores primeiro; empates pelo ID para um resultado estável
+	byAmount := func(list []dto.ParticipantBalanceResponse) func(i, j int) bool {
+		return func(i, j int) bool {
+			if list[i].Balance != list[j].Balance {
+				return list[i].Balance > list[j].Balance
+			}
+			return list[i].ParticipantID < list[j].ParticipantID
+		}
+	}
+	sort.Slice(creditors, byAmount(creditors))
+	sort.Slice(debtors, byAmount(debtors))
+
+	resp := &dto.SimplifiedDebtsResponse{Payments: []dto.DebtResponse{}}
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		amount := min(debtors[i].Balance, creditors[j].Balance)
+		resp.Payments = append(resp.Payments, dto.DebtResponse{
+			FromParticipantID: debtors[i].ParticipantID,
+			FromName:          debtors[i].Name,
+			ToParticipantID:   creditors[j].ParticipantID,
+			ToName:            creditors[j].Name,
+			Amount:            amount,
+		})
+
+		debtors[i].Balance -= amount
+		creditors[j].Balance -= amount
+		if debtors[i].Balance == 0 {
+			i++
+		}
+		if creditors[j].Balance == 0 {
+			j++
+		}
+	}
+
+	return resp, nil
+}
+
+// Carrega os nomes dos participantes e quanto cada um deve a cada outro,
+// já descontados os pagamentos registrados. A chave é (devedor, credor).
+func (s *SplitService) loadDebts(workspaceID uint) (map[uint]string, map[[2]uint]money.Amount, error) {
+	participants, err := repository.FindParticipantsByWorkspace(s.DB, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[uint]string, len(participants))
+	for _, p := range participants {
+		names[p.ID] = p.Name
+	}
+
+	debts, err := repository.SumSplitDebts(s.DB, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	settlements, err := repository.SumSettlements(s.DB, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owed := make(map[[2]uint]money.Amount)
+	for _, d := range debts {
+		owed[[2]uint{d.DebtorID, d.CreditorID}] += d.Amount
+	}
+	// Quem pagou reduz o que deve a quem recebeu
+	for _, st := range settlements {
+		owed[[2]uint{st.DebtorID, st.CreditorID}] -= st.Amount
+	}
+
+	return names, owed, nil
+}
+
+func debtResponse(names map[uint]string, debtor, creditor uint, amount money.Amount) dto.DebtResponse {
+	return dto.DebtResponse{
+		FromParticipantID: debtor,
+		FromName:          names[debtor],
+		ToParticipantID:   creditor,
+		ToName:            names[creditor],
+		Amount:            amount,
+	}
+}
+
+// Preenche a divisão da transação a partir do input. Quem pagou é o participante
+// vinculado ao usuário que criou a transação; se ele excluiu a conta, continua sendo o
+// pagador já registrado em t.PayerID. Sem input, a transação fica sem divisão.
+func applySplit(db *gorm.DB, t *models.Transaction, input *dto.SplitInput) error {
+	previousPayer := t.PayerID
+	t.SplitType, t.PayerID, t.ShareAmount, t.Splits = nil, nil, nil, nil
+	if input == nil {
+		return nil
+	}
+	if t.Type != "expense" {
+		return errors.New("apenas despesas podem ser divididas")
+	}
+
+	payer, err := splitPayer(db, t, previousPayer)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uint, len(input.Participants))
+	seen := make(map[uint]bool, len(input.Participants))
+	for i, entry := range input.Participants {
+		ids[i] = entry.ParticipantID
+		if ids[i] == 0 {
+			ids[i] = payer.ID
+		}
+		if seen[ids[i]] {
+			return errors.New("participante repetido na divisão")
+		}
+		seen[ids[i]] = true
+	}
+
+	participants, err := repository.FindParticipantsByIDs(db, t.WorkspaceID, ids)
+	if err != nil {
+		return err
+	}
+	if len(participants) != len(ids) {
+		return ErrParticipantNotFound
+	}
+	byID := make(map[uint]models.Participant, len(participants))
+	for _, p := range participants {
+		byID[p.ID] = p
+	}
+
+	amounts, err := allocateSplit(t.Amount, input)
+	if err != nil {
+		return err
+	}
+
+	var share money.Amount
+	splits := make([]models.TransactionSplit, len(ids))
+	for i, id := range ids {
+		splits[i] = models.TransactionSplit{ParticipantID: id, Amount: amounts[i]}
+		if id == payer.ID {
+			share = amounts[i]
+		}
+	}
+
+	t.SplitType = &input.Type
+	t.PayerID = &payer.ID
+	t.ShareAmount = &share
+	t.Splits = splits
+	return nil
+}
+
+// Participante que pagou a transação
+func splitPayer(db *gorm.DB, t *models.Transaction, previousPayer *uint) (*models.Participant, error) {
+	if t.UserID == nil {
+		if previousPayer == nil {
+			return nil, errors.New("o autor da transação excluiu a conta; não é possível dividi-la")
+		}
+		participants, err := repository.FindParticipantsByIDs(db, t.WorkspaceID, []uint{*previousPayer})
+		if err != nil {
+			return nil, err
+		}
+		if len(participants) == 0 {
+			return nil, ErrParticipantNotFound
+		}
+		return &participants[0], nil
+	}
+
+	user, err := repository.FindUserByID(db, *t.UserID)
+	if err != nil || user == nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+	return repository.EnsureUserParticipant(db, t.WorkspaceID, *t.UserID, user.FirstName+" "+user.LastName)
+}
+
+// Preenche os participantes das partes (após salvar, para não recriá-los junto com a transação)
+func fillSplitParticipants(db *gorm.DB, t *models.Transaction) error {
+	if len(t.Splits) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(t.Splits))
+	for i, split := range t.Splits {
+		ids[i] = split.ParticipantID
+	}
+	participants, err := repository.FindParticipantsByIDs(db, t.WorkspaceID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range participants {
+		for i := range t.Splits {
+			if t.Splits[i].ParticipantID == p.ID {
+				t.Splits[i].Participant = p
+			}
+		}
+	}
+	return nil
+}
+
+// Calcula o valor de cada parte. A soma das partes é sempre igual ao total, ao centavo.
+func allocateSplit(total money.Amount, input *dto.SplitInput) ([]money.Amount, error) {
+	weights := make([]float64, len(input.Participants))
+
+	switch input.Type {
+	case SplitEqual:
+		for i := range weights {
+			weights[i] = 1
+		}
+
+	case SplitExact:
+		amounts := make([]money.Amount, len(input.Participants))
+		var sum money.Amount
+		for i, entry := range input.Participants {
+			if entry.Amount == nil {
+				return nil, errors.New("informe o valor (amount) de cada parte na divisão exata")
+			}
+			amounts[i] = *entry.Amount
+			sum += amounts[i]
+		}
+		if sum != total {
+			return nil, fmt.Errorf("a soma das partes (%s) difere do valor da transação (%s)", sum, total)
+		}
+		return amounts, nil
+
+	case SplitPercent:
+		var sum float64
+		for i, entry := range input.Participants {
+			weights[i] = entry.Value
+			sum += entry.Value
+		}
+		if math.Abs(sum-100) > 0.001 {
+			return nil, errors.New("a soma dos percentuais deve ser 100")
+		}
+
+	case SplitShares:
+		for i, entry := range input.Participants {
+			weights[i] = entry.Value
+		}
+
+	default:
+		return nil, errors.New("tipo de divisão inválido")
+	}
+
+	return distribute(total, weights)
+}
+
+// Divide o total proporcionalmente aos pesos pelo método do maior resto:
+// cada parte recebe o valor truncado ao centavo e os centavos restantes vão
+// para as partes com maior fração descartada
+func distribute(total money.Amount, weights []float64) ([]money.Amount, error) {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 {
+		return nil, errors.New("a soma dos pesos deve ser maior que zero")
+	}
+
+	amounts := make([]money.Amount, len(weights))
+	fractions := make([]float64, len(weights))
+	var allocated money.Amount
+	for i, w := range weights {
+		exact := float64(total) * w / sum
+		amounts[i] = money.Amount(math.Floor(exact))
+		fractions[i] = exact - math.Floor(exact)
+		allocated += amounts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return fractions[order[a]] > fractions[order[b]]
+	})
+
+	for k := 0; allocated < total; k++ {
+		amounts[order[k%len(order)]]++
+		allocated++
+	}
+
+	return amounts, nil
+}
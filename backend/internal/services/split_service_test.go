@@ -0,0 +1,186 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/money"
+)
+
+func amountPtr(a money.Amount) *money.Amount {
+	return &a
+}
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   money.Amount
+		weights []float64
+		want    []money.Amount
+	}{
+		{
+			name:    "divisão exata sem sobra",
+			total:   900,
+			weights: []float64{1, 1, 1},
+			want:    []money.Amount{300, 300, 300},
+		},
+		{
+			name:    "centavo que sobra vai para o primeiro",
+			total:   1000,
+			weights: []float64{1, 1, 1},
+			want:    []money.Amount{334, 333, 333},
+		},
+		{
+			name:    "dois centavos que sobram vão para os dois primeiros",
+			total:   1100,
+			weights: []float64{1, 1, 1},
+			want:    []money.Amount{367, 367, 366},
+		},
+		{
+			name:    "sobra vai para a maior fração descartada",
+			total:   100,
+			weights: []float64{1, 2},
+			want:    []money.Amount{33, 67},
+		},
+		{
+			name:    "peso zero não recebe centavos",
+			total:   101,
+			weights: []float64{1, 0, 1},
+			want:    []money.Amount{51, 0, 50},
+		},
+		{
+			name:    "total menor que o número de partes",
+			total:   2,
+			weights: []float64{1, 1, 1},
+			want:    []money.Amount{1, 1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := distribute(tt.total, tt.weights)
+			if err != nil {
+				t.Fatalf("distribute: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("distribute(%d, %v) = %v, esperado %v", tt.total, tt.weights, got, tt.want)
+			}
+
+			var sum money.Amount
+			for _, a := range got {
+				sum += a
+			}
+			if sum != tt.total {
+				t.Errorf("soma das partes = %d, esperado %d", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestDistributeZeroWeights(t *testing.T) {
+	if _, err := distribute(100, []float64{0, 0}); err == nil {
+		t.Error("esperado erro com a soma dos pesos igual a zero")
+	}
+}
+
+func TestAllocateSplit(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        money.Amount
+		splitType    string
+		participants []dto.SplitEntryInput
+		want         []money.Amount
+	}{
+		{
+			name:         "igual ignora os valores",
+			total:        1000,
+			splitType:    SplitEqual,
+			participants: []dto.SplitEntryInput{{Value: 5}, {}, {}},
+			want:         []money.Amount{334, 333, 333},
+		},
+		{
+			name:         "exata usa os valores informados",
+			total:        1000,
+			splitType:    SplitExact,
+			participants: []dto.SplitEntryInput{{Amount: amountPtr(701)}, {Amount: amountPtr(299)}},
+			want:         []money.Amount{701, 299},
+		},
+		{
+			name:         "percentual com sobra de centavo",
+			total:        1001,
+			splitType:    SplitPercent,
+			participants: []dto.SplitEntryInput{{Value: 50}, {Value: 50}},
+			want:         []money.Amount{501, 500},
+		},
+		{
+			name:         "percentual fracionado",
+			total:        10000,
+			splitType:    SplitPercent,
+			participants: []dto.SplitEntryInput{{Value: 33.33}, {Value: 33.33}, {Value: 33.34}},
+			want:         []money.Amount{3333, 3333, 3334},
+		},
+		{
+			name:         "pesos",
+			total:        1000,
+			splitType:    SplitShares,
+			participants: []dto.SplitEntryInput{{Value: 2}, {Value: 1}},
+			want:         []money.Amount{667, 333},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := allocateSplit(tt.total, &dto.SplitInput{Type: tt.splitType, Participants: tt.participants})
+			if err != nil {
+				t.Fatalf("allocateSplit: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("allocateSplit = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateSplitErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		splitType    string
+		participants []dto.SplitEntryInput
+	}{
+		{
+			name:         "exata com soma diferente do total",
+			splitType:    SplitExact,
+			participants: []dto.SplitEntryInput{{Amount: amountPtr(500)}, {Amount: amountPtr(499)}},
+		},
+		{
+			name:         "exata sem valor",
+			splitType:    SplitExact,
+			participants: []dto.SplitEntryInput{{Amount: amountPtr(1000)}, {Value: 0}},
+		},
+		{
+			name:         "percentuais que não somam 100",
+			splitType:    SplitPercent,
+			participants: []dto.SplitEntryInput{{Value: 50}, {Value: 40}},
+		},
+		{
+			name:         "pesos zerados",
+			splitType:    SplitShares,
+			participants: []dto.SplitEntryInput{{Value: 0}, {Value: 0}},
+		},
+		{
+			name:         "tipo desconhecido",
+			splitType:    "other",
+			participants: []dto.SplitEntryInput{{Value: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := allocateSplit(1000, &dto.SplitInput{Type: tt.splitType, Participants: tt.participants})
+			if err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
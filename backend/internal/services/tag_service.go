@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTagExists    = errors.New("já existe uma etiqueta com esse nome")
+	ErrTagNameEmpty = errors.New("o nome da etiqueta não pode ser vazio")
+)
+
+type TagService struct {
+	DB    *gorm.DB
+	cache *cache.Cache
+}
+
+// Construtor
+func NewTagService(db *gorm.DB, cache *cache.Cache) *TagService {
+	return &TagService{DB: db, cache: cache}
+}
+
+// Cria uma etiqueta
+func (s *TagService) CreateTag(userID, workspaceID uint, name string) (*models.Tag, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if err := checkTagName(s.DB, workspaceID, 0, name); err != nil {
+		return nil, err
+	}
+
+	tag := &models.Tag{
+		WorkspaceID: workspaceID,
+		UserID:      &userID,
+		Name:        name,
+	}
+	if err := repository.CreateTag(s.DB, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Lista as etiquetas do workspace
+func (s *TagService) ListTags(userID, workspaceID uint) ([]models.Tag, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindTagsByWorkspace(s.DB, workspaceID)
+}
+
+// Renomeia uma etiqueta
+func (s *TagService) UpdateTag(userID, workspaceID, id uint, name string) (*models.Tag, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if err := checkTagName(s.DB, workspaceID, id, name); err != nil {
+		return nil, err
+	}
+
+	tag := &models.Tag{
+		ID:          id,
+		WorkspaceID: workspaceID,
+		Name:        name,
+	}
+	if err := repository.UpdateTag(s.DB, tag); err != nil {
+		return nil, err
+	}
+
+	// As transações e relatórios em cache exibem o nome da etiqueta
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
+
+	return tag, nil
+}
+
+// Deleta uma etiqueta, removendo-a das transações
+func (s *TagService) DeleteTag(userID, workspaceID, id uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	if err := repository.DeleteTag(s.DB, id, workspaceID); err != nil {
+		return err
+	}
+
+	// Invalida cache de transações (e relatórios) do workspace
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
+
+	return nil
+}
+
+// Garante que o nome não é vazio e que não existe outra etiqueta com o mesmo nome no workspace
+func checkTagName(db *gorm.DB, workspaceID, tagID uint, name string) error {
+	if name == "" {
+		return ErrTagNameEmpty
+	}
+
+	exists, err := repository.TagNameExists(db, workspaceID, name, tagID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrTagExists
+	}
+	return nil
+}
+
+// Busca (ou cria) as etiquetas informadas na transação.
+// Nomes vazios e repetidos (sem diferenciar maiúsculas) são ignorados.
+func resolveTags(db *gorm.DB, workspaceID, userID uint, names []string) ([]models.Tag, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		normalized = append(normalized, name)
+	}
+
+	return repository.FindOrCreateTags(db, workspaceID, userID, normalized)
+}
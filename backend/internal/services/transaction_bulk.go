@@ -0,0 +1,411 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Número máximo de transações afetadas por um lote
+const maxBulkTransactions = 1000
+
+var (
+	ErrBulkEmpty       = errors.New("nenhuma operação informada")
+	ErrBulkNoSelection = errors.New("informe ids ou filter para selecionar as transações")
+	ErrBulkNoChanges   = errors.New("informe ao menos um campo para alterar")
+	ErrBulkTooLarge    = fmt.Errorf("o lote pode afetar no máximo %d transações", maxBulkTransactions)
+	ErrBulkInvalidDate = errors.New("data inválida no filtro")
+	ErrBulkFailed      = errors.New("o lote contém itens com erro, nenhuma alteração foi aplicada")
+)
+
+// Alterações de uma operação de update já validadas
+type bulkPatch struct {
+	input    dto.TransactionBulkUpdate
+	category *models.Category
+	date     *time.Time
+	tags     []models.Tag // Substituição (quando input.Tags foi informado)
+	addTags  []models.Tag
+}
+
+// Executa criações, alterações e remoções de transações em uma única transação do banco.
+// O saldo de cada conta é conferido item a item (um item que deixaria a conta negativa falha)
+// e ajustado uma única vez com o efeito líquido do lote. Sem partial, qualquer item com erro
+// desfaz o lote inteiro; com partial, apenas os itens com erro são descartados.
+func (s *TransactionService) BulkTransactions(
+	userID, workspaceID uint,
+	input dto.TransactionBulkInput,
+) (*dto.TransactionBulkResponse, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Create) == 0 && len(input.Update) == 0 && input.Delete == nil {
+		return nil, ErrBulkEmpty
+	}
+
+	// Resolve as transações selecionadas em cada update e no delete
+	affected := len(input.Create)
+	updateIDs := make([][]uint, len(input.Update))
+	for i, op := range input.Update {
+		if op.AccountID == nil && op.CategoryID == nil && op.Description == nil && op.Date == nil &&
+			op.Tags == nil && len(op.AddTags) == 0 && len(op.RemoveTags) == 0 {
+			return nil, ErrBulkNoChanges
+		}
+		if updateIDs[i], err = s.selectTransactions(workspaceID, op.TransactionBulkSelector); err != nil {
+			return nil, err
+		}
+		affected += len(updateIDs[i])
+	}
+	var deleteIDs []uint
+	if input.Delete != nil {
+		if deleteIDs, err = s.selectTransactions(workspaceID, *input.Delete); err != nil {
+			return nil, err
+		}
+		affected += len(deleteIDs)
+	}
+	if affected > maxBulkTransactions {
+		return nil, ErrBulkTooLarge
+	}
+
+	resp := &dto.TransactionBulkResponse{Results: make([]dto.TransactionBulkItemResult, 0, affected)}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// Bloqueia as transações e, depois, todas as contas envolvidas, uma única vez
+		var selectedIDs []uint
+		for _, ids := range updateIDs {
+			selectedIDs = append(selectedIDs, ids...)
+		}
+		selectedIDs = append(selectedIDs, deleteIDs...)
+
+		locked, err := repository.LockTransactions(tx, workspaceID, selectedIDs)
+		if err != nil {
+			return err
+		}
+
+		var accountIDs []uint
+		for _, item := range input.Create {
+			accountIDs = append(accountIDs, item.AccountID)
+		}
+		for _, op := range input.Update {
+			if op.AccountID != nil {
+				accountIDs = append(accountIDs, *op.AccountID)
+			}
+		}
+		for _, t := range locked {
+			accountIDs = append(accountIDs, t.AccountID)
+		}
+
+		accounts, err := repository.LockExistingAccounts(tx, workspaceID, accountIDs)
+		if err != nil {
+			return err
+		}
+
+		// Efeito líquido do lote no saldo de cada conta
+		net := make(map[uint]money.Amount)
+
+		// Confere o saldo das contas com o efeito do item somado ao dos itens anteriores
+		// e, se nenhuma conta (exceto cartão de crédito) ficar negativa, acumula o efeito
+		addNet := func(delta map[uint]money.Amount) error {
+			for id, amount := range delta {
+				account := accounts[id]
+				if account == nil {
+					return errors.New("conta não encontrada")
+				}
+				if amount < 0 && account.Balance+net[id]+amount < 0 && !repository.AllowsNegativeBalance(account) {
+					return fmt.Errorf("saldo insuficiente na conta %s", account.Name)
+				}
+			}
+			for id, amount := range delta {
+				net[id] += amount
+			}
+			return nil
+		}
+
+		// Cada item roda em um savepoint: um erro desfaz apenas o próprio item
+		record := func(operation string, index int, id uint, fn func(tx *gorm.DB) (uint, error)) {
+			result := dto.TransactionBulkItemResult{Operation: operation, Index: index, ID: id}
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				var err error
+				result.ID, err = fn(tx)
+				return err
+			})
+			if err != nil {
+				result.ID = id
+				result.Error = err.Error()
+				resp.Failed++
+			} else {
+				resp.Succeeded++
+			}
+			resp.Results = append(resp.Results, result)
+		}
+
+		for i, item := range input.Create {
+			record("create", i, 0, func(tx *gorm.DB) (uint, error) {
+				if accounts[item.AccountID] == nil {
+					return 0, errors.New("conta não encontrada")
+				}
+				t, err := newTransaction(tx, workspaceID, userID, item)
+				if err != nil {
+					return 0, err
+				}
+				if err := repository.InsertTransaction(tx, t); err != nil {
+					return 0, err
+				}
+				if err := addNet(map[uint]money.Amount{
+					t.AccountID: repository.SignedAmount(t.Type, t.Amount),
+				}); err != nil {
+					return 0, err
+				}
+				return t.ID, nil
+			})
+		}
+
+		for i, op := range input.Update {
+			var patch *bulkPatch
+			patchErr := tx.Transaction(func(tx *gorm.DB) error {
+				var err error
+				patch, err = prepareBulkPatch(tx, workspaceID, userID, op)
+				return err
+			})
+			for _, id := range updateIDs[i] {
+				record("update", i, id, func(tx *gorm.DB) (uint, error) {
+					if patchErr != nil {
+						return 0, patchErr
+					}
+					current, ok := locked[id]
+					if !ok {
+						return 0, errors.New("transação não encontrada")
+					}
+					updated, err := applyBulkPatch(tx, *current, patch, accounts)
+					if err != nil {
+						return 0, err
+					}
+					delta := map[uint]money.Amount{}
+					delta[current.AccountID] -= repository.SignedAmount(current.Type, current.Amount)
+					delta[updated.AccountID] += repository.SignedAmount(updated.Type, updated.Amount)
+					if err := addNet(delta); err != nil {
+						return 0, err
+					}
+					// Operações seguintes enxergam a transação já alterada
+					locked[id] = updated
+					return id, nil
+				})
+			}
+		}
+
+		for i, id := range deleteIDs {
+			record("delete", i, id, func(tx *gorm.DB) (uint, error) {
+				current, ok := locked[id]
+				if !ok {
+					return 0, errors.New("transação não encontrada")
+				}
+				if err := repository.RemoveTransaction(tx, current); err != nil {
+					return 0, err
+				}
+				if err := addNet(map[uint]money.Amount{
+					current.AccountID: -repository.SignedAmount(current.Type, current.Amount),
+				}); err != nil {
+					return 0, err
+				}
+				delete(locked, id)
+				return id, nil
+			})
+		}
+
+		if resp.Failed > 0 && !input.Partial {
+			return ErrBulkFailed
+		}
+
+		return repository.ApplyAccountBalances(tx, accounts, net)
+	})
+	if errors.Is(err, ErrBulkFailed) {
+		// As transações criadas foram desfeitas junto com o lote
+		for i := range resp.Results {
+			if resp.Results[i].Operation == "create" {
+				resp.Results[i].ID = 0
+			}
+		}
+		return resp, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Committed = true
+
+	// Invalida cache de transações e contas do workspace
+	if resp.Succeeded > 0 {
+		s.cache.InvalidateWorkspaceTransactions(workspaceID)
+		s.cache.InvalidateWorkspaceAccounts(workspaceID)
+	}
+
+	return resp, nil
+}
+
+// Retorna os IDs selecionados por ids e/ou filter. Só com ids, mantém os inexistentes,
+// que viram erro no resultado do item.
+func (s *TransactionService) selectTransactions(workspaceID uint, selector dto.TransactionBulkSelector) ([]uint, error) {
+	if len(selector.IDs) == 0 && selector.Filter == nil {
+		return nil, ErrBulkNoSelection
+	}
+
+	// Com filtro, busca as transações que o atendem (os IDs informados, se houver, apenas restringem a busca)
+	if selector.Filter != nil {
+		filter, err := bulkFilter(*selector.Filter)
+		if err != nil {
+			return nil, err
+		}
+		return repository.FindTransactionIDs(s.DB, workspaceID, filter, selector.IDs)
+	}
+
+	ids := make([]uint, 0, len(selector.IDs))
+	seen := make(map[uint]bool, len(selector.IDs))
+	for _, id := range selector.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Converte o filtro do corpo da requisição no filtro da listagem
+func bulkFilter(input dto.TransactionBulkFilter) (dto.TransactionFilter, error) {
+	filter := dto.TransactionFilter{
+		CategoryID: input.CategoryID,
+		MinAmount:  input.MinAmount,
+		MaxAmount:  input.MaxAmount,
+		Type:       input.Type,
+		TagMode:    input.TagMode,
+		Query:      strings.TrimSpace(input.Query),
+	}
+
+	if input.FromDate != "" {
+		from, err := time.Parse("2006-01-02", input.FromDate)
+		if err != nil {
+			return filter, ErrBulkInvalidDate
+		}
+		filter.FromDate = &from
+	}
+	if input.ToDate != "" {
+		to, err := time.Parse("2006-01-02", input.ToDate)
+		if err != nil {
+			return filter, ErrBulkInvalidDate
+		}
+		filter.ToDate = &to
+	}
+
+	// Sem repetições: no modo "all" cada etiqueta precisa contar uma única vez
+	seen := make(map[string]bool, len(input.Tags))
+	for _, tag := range input.Tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !seen[tag] {
+			seen[tag] = true
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	return filter, nil
+}
+
+// Valida uma operação de update uma única vez para todas as transações selecionadas
+func prepareBulkPatch(tx *gorm.DB, workspaceID, userID uint, input dto.TransactionBulkUpdate) (*bulkPatch, error) {
+	patch := &bulkPatch{input: input}
+
+	if input.CategoryID != nil {
+		category, err := checkCategory(tx, workspaceID, *input.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		patch.category = category
+	}
+
+	if input.Date != nil {
+		date, err := time.Parse("2006-01-02", *input.Date)
+		if err != nil {
+			return nil, errors.New("data inválida")
+		}
+		patch.date = &date
+	}
+
+	var err error
+	if input.Tags != nil {
+		if patch.tags, err = resolveTags(tx, workspaceID, userID, *input.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if patch.addTags, err = resolveTags(tx, workspaceID, userID, input.AddTags); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+// Aplica as alterações em uma cópia da transação e a grava
+func applyBulkPatch(
+	tx *gorm.DB,
+	t models.Transaction,
+	patch *bulkPatch,
+	accounts map[uint]*models.Account,
+) (*models.Transaction, error) {
+	input := patch.input
+
+	if input.AccountID != nil {
+		if accounts[*input.AccountID] == nil {
+			return nil, errors.New("conta não encontrada")
+		}
+		t.AccountID = *input.AccountID
+	}
+	if patch.category != nil {
+		// Transações antigas podem continuar na categoria arquivada em que já estão
+		if err := checkCategoryUsage(patch.category, t.Type, t.CategoryID == patch.category.ID); err != nil {
+			return nil, err
+		}
+		t.CategoryID = patch.category.ID
+	}
+	if input.Description != nil {
+		t.Description = *input.Description
+	}
+	if patch.date != nil {
+		t.Date = *patch.date
+	}
+
+	t.Tags = mergeBulkTags(t.Tags, patch)
+
+	if err := repository.PatchTransaction(tx, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// Substitui, acrescenta e remove etiquetas (sem diferenciar maiúsculas)
+func mergeBulkTags(current []models.Tag, patch *bulkPatch) []models.Tag {
+	tags := current
+	if patch.input.Tags != nil {
+		tags = patch.tags
+	}
+
+	removed := make(map[string]bool, len(patch.input.RemoveTags))
+	for _, name := range patch.input.RemoveTags {
+		removed[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	merged := make([]models.Tag, 0, len(tags)+len(patch.addTags))
+	seen := make(map[uint]bool, len(tags)+len(patch.addTags))
+	for _, tag := range append(append([]models.Tag{}, tags...), patch.addTags...) {
+		if seen[tag.ID] || removed[strings.ToLower(tag.Name)] {
+			continue
+		}
+		seen[tag.ID] = true
+		merged = append(merged, tag)
+	}
+
+	return merged
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
+)
+
+var (
+	ErrInvalidSort   = errors.New("ordenação inválida, use sort=date, amount ou created_at e order=asc ou desc")
+	ErrInvalidCursor = errors.New("cursor inválido")
+)
+
+// Valida a ordenação pedida, preenchendo os padrões. Na busca textual sem sort
+// explícito, o campo fica vazio (ordenação por relevância).
+func normalizeTransactionSort(sort dto.TransactionSort, filter dto.TransactionFilter) (dto.TransactionSort, error) {
+	switch sort.Field {
+	case "":
+		if filter.Query == "" {
+			sort.Field = "date"
+		}
+	case "date", "amount", "created_at":
+	default:
+		return sort, ErrInvalidSort
+	}
+
+	switch sort.Order {
+	case "":
+		sort.Order = "desc"
+	case "asc", "desc":
+	default:
+		return sort, ErrInvalidSort
+	}
+
+	return sort, nil
+}
+
+// Gera o cursor opaco que aponta para a transação na borda da página
+func encodeTransactionCursor(sort dto.TransactionSort, tx models.Transaction, backward bool) string {
+	cursor := dto.TransactionCursor{
+		Field:    sort.Field,
+		Order:    sort.Order,
+		Backward: backward,
+		ID:       tx.ID,
+	}
+
+	switch sort.Field {
+	case "date":
+		cursor.Value = tx.Date.Format("2006-01-02")
+	case "amount":
+		cursor.Value = tx.Amount.String()
+	case "created_at":
+		cursor.Value = tx.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Lê o cursor recebido do cliente, validando a ordenação e o valor da posição
+func decodeTransactionCursor(raw string) (*dto.TransactionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor dto.TransactionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Order != "asc" && cursor.Order != "desc" {
+		return nil, ErrInvalidCursor
+	}
+
+	switch cursor.Field {
+	case "date":
+		_, err = time.Parse("2006-01-02", cursor.Value)
+	case "amount":
+		_, err = money.Parse(cursor.Value)
+	case "created_at":
+		_, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	default:
+		err = ErrInvalidCursor
+	}
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
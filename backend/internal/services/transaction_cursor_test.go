@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+)
+
+func TestTransactionCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 14, 15, 9, 26, 535897932, time.FixedZone("BRT", -3*60*60))
+	tx := models.Transaction{
+		ID:        42,
+		Date:      mustDate("2026-03-14"),
+		Amount:    123456,
+		CreatedAt: createdAt,
+	}
+
+	tests := []struct {
+		name      string
+		sort      dto.TransactionSort
+		backward  bool
+		wantValue string
+	}{
+		{"data decrescente", dto.TransactionSort{Field: "date", Order: "desc"}, false, "2026-03-14"},
+		{"data crescente para trás", dto.TransactionSort{Field: "date", Order: "asc"}, true, "2026-03-14"},
+		{"valor", dto.TransactionSort{Field: "amount", Order: "desc"}, false, "1234.56"},
+		{"criação em UTC com nanossegundos", dto.TransactionSort{Field: "created_at", Order: "asc"}, true, "2026-03-14T18:09:26.535897932Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := encodeTransactionCursor(tt.sort, tx, tt.backward)
+
+			cursor, err := decodeTransactionCursor(raw)
+			if err != nil {
+				t.Fatalf("decodeTransactionCursor(%q): %v", raw, err)
+			}
+
+			want := dto.TransactionCursor{
+				Field:    tt.sort.Field,
+				Order:    tt.sort.Order,
+				Backward: tt.backward,
+				Value:    tt.wantValue,
+				ID:       tx.ID,
+			}
+			if *cursor != want {
+				t.Errorf("cursor = %+v, esperado %+v", *cursor, want)
+			}
+		})
+	}
+}
+
+func TestDecodeTransactionCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"vazio", ""},
+		{"base64 inválido", "!!!"},
+		{"base64 com padding", base64.URLEncoding.EncodeToString([]byte(`{"f":"date","o":"desc","v":"2026-03-14","i":1}`))},
+		{"json inválido", encode("{")},
+		{"sem ID", encode(`{"f":"date","o":"desc","v":"2026-03-14"}`)},
+		{"ordem inválida", encode(`{"f":"date","o":"up","v":"2026-03-14","i":1}`)},
+		{"campo inválido", encode(`{"f":"description","o":"desc","v":"x","i":1}`)},
+		{"data inválida", encode(`{"f":"date","o":"desc","v":"14/03/2026","i":1}`)},
+		{"valor inválido", encode(`{"f":"amount","o":"desc","v":"1,5","i":1}`)},
+		{"criação inválida", encode(`{"f":"created_at","o":"desc","v":"2026-03-14","i":1}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeTransactionCursor(tt.raw); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeTransactionCursor(%q) = %v, esperado ErrInvalidCursor", tt.raw, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeTransactionSort(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   dto.TransactionSort
+		filter dto.TransactionFilter
+		want   dto.TransactionSort
+	}{
+		{"padrão", dto.TransactionSort{}, dto.TransactionFilter{}, dto.TransactionSort{Field: "date", Order: "desc"}},
+		{"busca textual ordena por relevância", dto.TransactionSort{}, dto.TransactionFilter{Query: "mercado"}, dto.TransactionSort{Order: "desc"}},
+		{"busca textual com sort explícito", dto.TransactionSort{Field: "amount", Order: "asc"}, dto.TransactionFilter{Query: "mercado"}, dto.TransactionSort{Field: "amount", Order: "asc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTransactionSort(tt.sort, tt.filter)
+			if err != nil {
+				t.Fatalf("normalizeTransactionSort: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeTransactionSort = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, sort := range []dto.TransactionSort{{Field: "description"}, {Field: "date", Order: "up"}} {
+		if _, err := normalizeTransactionSort(sort, dto.TransactionFilter{}); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("normalizeTransactionSort(%+v) = %v, esperado ErrInvalidSort", sort, err)
+		}
+	}
+}
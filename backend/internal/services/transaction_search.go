@@ -0,0 +1,98 @@
+package services
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Letras acentuadas (minúsculas) e a letra sem acento correspondente
+var accentFold = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// Preenche o destaque das transações encontradas pela busca textual. O HTML da
+// descrição é escapado e os termos encontrados ficam entre <mark> e </mark>.
+func highlightTransactions(db *gorm.DB, transactions []models.Transaction, q string) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(transactions))
+	for _, t := range transactions {
+		ids = append(ids, t.ID)
+	}
+
+	highlights, err := repository.FindTransactionHighlights(db, ids, q)
+	if err != nil {
+		return err
+	}
+
+	marks := strings.NewReplacer(repository.HighlightStart, "<mark>", repository.HighlightStop, "</mark>")
+	for i := range transactions {
+		t := &transactions[i]
+		if h := highlights[t.ID]; strings.Contains(h, repository.HighlightStart) {
+			t.Highlight = marks.Replace(html.EscapeString(h))
+		} else {
+			// Encontrada por trecho de palavra: o ts_headline não marca nada
+			t.Highlight = highlightSubstring(t.Description, q)
+		}
+	}
+
+	return nil
+}
+
+// Marca as ocorrências de q no texto, sem diferenciar maiúsculas e acentos.
+// Retorna vazio se não houver ocorrência.
+func highlightSubstring(text, q string) string {
+	src := []rune(text)
+	folded := foldRunes(src)
+	needle := foldRunes([]rune(strings.TrimSpace(q)))
+	if len(needle) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	last, found := 0, false
+	for i := 0; i+len(needle) <= len(folded); {
+		if !slices.Equal(folded[i:i+len(needle)], needle) {
+			i++
+			continue
+		}
+		b.WriteString(html.EscapeString(string(src[last:i])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(src[i : i+len(needle)])))
+		b.WriteString("</mark>")
+		i += len(needle)
+		last, found = i, true
+	}
+	if !found {
+		return ""
+	}
+	b.WriteString(html.EscapeString(string(src[last:])))
+
+	return b.String()
+}
+
+// Converte para minúsculas e remove acentos, mantendo uma runa por runa
+func foldRunes(runes []rune) []rune {
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		r = unicode.ToLower(r)
+		if plain, ok := accentFold[r]; ok {
+			r = plain
+		}
+		folded[i] = r
+	}
+	return folded
+}
@@ -3,10 +3,12 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/daviolvr/Fintrack/internal/cache"
 	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/exporter"
 	"github.com/daviolvr/Fintrack/internal/models"
 	"github.com/daviolvr/Fintrack/internal/repository"
 	"github.com/daviolvr/Fintrack/internal/utils"
@@ -24,38 +26,82 @@ func NewTransactionService(db *gorm.DB, cache *cache.Cache) *TransactionService
 
 // Cria uma transação
 func (s *TransactionService) CreateTransaction(
-	userID, categoryID uint,
-	txType string,
-	amount float64,
-	description, dateStr string,
+	userID, workspaceID uint,
+	input dto.TransactionInput,
 ) (*models.Transaction, error) {
-	parsedDate, err := time.Parse("2006-01-02", dateStr)
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Etiquetas e participantes novos só são criados se a transação também for
+	var transaction *models.Transaction
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		transaction, err = newTransaction(tx, workspaceID, userID, input)
+		if err != nil {
+			return err
+		}
+		return repository.CreateTransaction(tx, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := fillSplitParticipants(s.DB, transaction); err != nil {
+		return nil, err
+	}
+
+	// Invalida cache de transações e contas do workspace
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
+
+	return transaction, nil
+}
+
+// Valida a entrada e monta a transação a ser criada, com divisão e etiquetas
+func newTransaction(db *gorm.DB, workspaceID, userID uint, input dto.TransactionInput) (*models.Transaction, error) {
+	parsedDate, err := time.Parse("2006-01-02", input.Date)
 	if err != nil {
 		return nil, errors.New("data inválida")
 	}
 
+	category, err := checkCategory(db, workspaceID, input.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	// Ocorrências de regras recorrentes continuam sendo geradas em categorias arquivadas
+	if err := checkCategoryUsage(category, input.Type, input.RecurringID != nil); err != nil {
+		return nil, err
+	}
+
 	transaction := &models.Transaction{
-		UserID:      userID,
-		CategoryID:  categoryID,
-		Type:        txType,
-		Amount:      amount,
-		Description: description,
+		WorkspaceID: workspaceID,
+		UserID:      &userID,
+		AccountID:   input.AccountID,
+		CategoryID:  input.CategoryID,
+		Type:        input.Type,
+		Amount:      input.Amount,
+		Description: input.Description,
 		Date:        parsedDate,
+		RecurringID: input.RecurringID,
 	}
-
-	if err := repository.CreateTransaction(s.DB, transaction); err != nil {
+	if err := applySplit(db, transaction, input.Split); err != nil {
+		return nil, err
+	}
+	if transaction.Tags, err = resolveTags(db, workspaceID, userID, input.Tags); err != nil {
 		return nil, err
 	}
-
-	// Invalida cache de transações do usuário
-	s.cache.InvalidateUserTransactions(userID)
 
 	return transaction, nil
 }
 
 // Recupera uma transação
-func (s *TransactionService) RetrieveTransaction(userID, transactionID uint) (*models.Transaction, error) {
-	cacheKey := fmt.Sprintf("transactions:user=%d:transaction=%d", userID, transactionID)
+func (s *TransactionService) RetrieveTransaction(userID, workspaceID, transactionID uint) (*models.Transaction, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("transactions:workspace=%d:transaction=%d", workspaceID, transactionID)
 
 	var cached dto.TransactionRetrieveCacheData
 	found, err := s.cache.Get(cacheKey, &cached)
@@ -64,7 +110,7 @@ func (s *TransactionService) RetrieveTransaction(userID, transactionID uint) (*m
 		return &cached.Transaction, nil
 	}
 
-	tx, err := repository.RetrieveTransactionByIDAndUserID(s.DB, userID, transactionID)
+	tx, err := repository.RetrieveTransactionByIDAndWorkspaceID(s.DB, workspaceID, transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,25 +128,39 @@ func (s *TransactionService) RetrieveTransaction(userID, transactionID uint) (*m
 	return tx, nil
 }
 
-// Lista transações com filtros e paginação
+// Lista transações com filtros e paginação. Sem cursor, pagina por page/limit;
+// com cursor (next_cursor/prev_cursor de uma listagem anterior), a ordenação vem
+// do próprio cursor e a página começa logo após a posição dele.
 func (s *TransactionService) ListTransactions(
-	userID uint,
-	fromDate, toDate *time.Time,
-	categoryID *uint,
-	minAmount, maxAmount *float64,
-	txType *string,
+	userID, workspaceID uint,
+	filter dto.TransactionFilter,
+	sort dto.TransactionSort,
+	rawCursor string,
 	page, limit int,
-) ([]models.Transaction, int, error) {
+) (*dto.TransactionListCacheData, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor *dto.TransactionCursor
+	if rawCursor != "" {
+		if cursor, err = decodeTransactionCursor(rawCursor); err != nil {
+			return nil, err
+		}
+		sort = dto.TransactionSort{Field: cursor.Field, Order: cursor.Order}
+	} else if sort, err = normalizeTransactionSort(sort, filter); err != nil {
+		return nil, err
+	}
+
 	// Monta a chave do cache
 	cacheKey := fmt.Sprintf(
-		"transactions:user=%d:from=%s:to=%s:cat=%s:min=%s:max=%s:type=%s:page=%d:limit=%d",
-		userID,
-		utils.FormatTime(fromDate),
-		utils.FormatTime(toDate),
-		utils.FormatUint(categoryID),
-		utils.FormatFloat(minAmount),
-		utils.FormatFloat(maxAmount),
-		utils.FormatString(txType),
+		"transactions:workspace=%d:%s:sort=%s:order=%s:cursor=%s:page=%d:limit=%d",
+		workspaceID,
+		transactionFilterKey(filter),
+		sort.Field,
+		sort.Order,
+		rawCursor,
 		page,
 		limit,
 	)
@@ -110,77 +170,175 @@ func (s *TransactionService) ListTransactions(
 	found, err := s.cache.Get(cacheKey, &cached)
 	if err == nil && found {
 		fmt.Println("Pegando do cache:", cacheKey)
-		return cached.Transactions, cached.Total, nil
-	}
-
-	transactions, total, err := repository.FindTransactionsByUser(
-		s.DB,
-		userID,
-		fromDate,
-		toDate,
-		categoryID,
-		minAmount,
-		maxAmount,
-		txType,
-		page,
-		limit,
+		return &cached, nil
+	}
+
+	transactions, total, hasMore, err := repository.FindTransactionsByWorkspace(
+		s.DB, workspaceID, filter, sort, cursor, page, limit,
 	)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	// Salva no cache
-	if err := s.cache.Set(cacheKey, dto.TransactionListCacheData{
+	if filter.Query != "" {
+		if err := highlightTransactions(s.DB, transactions, filter.Query); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &dto.TransactionListCacheData{
 		Transactions: transactions,
 		Total:        total,
-	}, time.Minute*2); err != nil {
+	}
+
+	// Cursores das páginas vizinhas (a ordenação por relevância só pagina por page/limit)
+	if sort.Field != "" && len(transactions) > 0 {
+		hasNext, hasPrev := hasMore, page > 1
+		if cursor != nil {
+			// Ao avançar, sempre há a página de onde se veio; ao voltar, os registros
+			// a mais estão antes da página
+			hasPrev = true
+			if cursor.Backward {
+				hasNext, hasPrev = true, hasMore
+			}
+		}
+		if hasNext {
+			result.NextCursor = encodeTransactionCursor(sort, transactions[len(transactions)-1], false)
+		}
+		if hasPrev {
+			result.PrevCursor = encodeTransactionCursor(sort, transactions[0], true)
+		}
+	}
+
+	// Salva no cache
+	if err := s.cache.Set(cacheKey, result, time.Minute*2); err != nil {
 		fmt.Println("Erro ao salvar no cache:", err)
 	}
 
-	return transactions, total, nil
+	return result, nil
 }
 
 // Atualiza transação
 func (s *TransactionService) UpdateTransaction(
-	userID, transactionID, categoryID uint,
-	txType string,
-	amount float64,
-	description, dateStr string,
+	userID, workspaceID, transactionID uint,
+	input dto.TransactionInput,
 ) (*models.Transaction, error) {
-	parsedDate, err := time.Parse("2006-01-02", dateStr)
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedDate, err := time.Parse("2006-01-02", input.Date)
 	if err != nil {
 		return nil, errors.New("data inválida")
 	}
 
+	category, err := checkCategory(s.DB, workspaceID, input.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quem pagou continua sendo quem criou a transação
+	current, err := repository.RetrieveTransactionByIDAndWorkspaceID(s.DB, workspaceID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	// Transações antigas podem continuar na categoria arquivada em que já estão
+	if err := checkCategoryUsage(category, input.Type, current.CategoryID == input.CategoryID); err != nil {
+		return nil, err
+	}
+
 	tx := &models.Transaction{
 		ID:          transactionID,
-		UserID:      userID,
-		CategoryID:  categoryID,
-		Type:        txType,
-		Amount:      amount,
-		Description: description,
+		WorkspaceID: workspaceID,
+		UserID:      current.UserID,
+		PayerID:     current.PayerID,
+		AccountID:   input.AccountID,
+		CategoryID:  input.CategoryID,
+		Type:        input.Type,
+		Amount:      input.Amount,
+		Description: input.Description,
 		Date:        parsedDate,
 	}
 
-	if err := repository.UpdateTransaction(s.DB, tx); err != nil {
+	// Etiquetas e participantes novos só são criados se a atualização também for
+	err = s.DB.Transaction(func(dbTx *gorm.DB) error {
+		if err := applySplit(dbTx, tx, input.Split); err != nil {
+			return err
+		}
+		if tx.Tags, err = resolveTags(dbTx, workspaceID, userID, input.Tags); err != nil {
+			return err
+		}
+		return repository.UpdateTransaction(dbTx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := fillSplitParticipants(s.DB, tx); err != nil {
 		return nil, err
 	}
 
-	// Invalida cache de transações do usuário
-	s.cache.InvalidateUserTransactions(userID)
+	// Invalida cache de transações e contas do workspace
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
 
 	return tx, nil
 }
 
 // Deleta transação
-func (s *TransactionService) DeleteTransaction(userID, transactionID uint) error {
-	err := repository.DeleteTransactionByUser(s.DB, userID, transactionID)
+func (s *TransactionService) DeleteTransaction(userID, workspaceID, transactionID uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
 	if err != nil {
 		return err
 	}
 
-	// Invalida cache de transações do usuário
-	s.cache.InvalidateUserTransactions(userID)
+	err = repository.DeleteTransactionByWorkspace(s.DB, workspaceID, transactionID)
+	if err != nil {
+		return err
+	}
+
+	// Invalida cache de transações e contas do workspace
+	s.cache.InvalidateWorkspaceTransactions(workspaceID)
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
 
 	return nil
 }
+
+// Monta o trecho da chave de cache referente aos filtros
+func transactionFilterKey(filter dto.TransactionFilter) string {
+	return fmt.Sprintf(
+		"from=%s:to=%s:cat=%s:min=%s:max=%s:type=%s:tags=%s:tag_mode=%s:q=%s",
+		utils.FormatTime(filter.FromDate),
+		utils.FormatTime(filter.ToDate),
+		utils.FormatUint(filter.CategoryID),
+		utils.FormatAmount(filter.MinAmount),
+		utils.FormatAmount(filter.MaxAmount),
+		utils.FormatString(filter.Type),
+		strings.Join(filter.Tags, ","),
+		filter.TagMode,
+		filter.Query,
+	)
+}
+
+// Exporta todas as transações que atendem ao filtro, linha a linha, no encoder informado
+func (s *TransactionService) ExportTransactions(
+	userID, workspaceID uint,
+	filter dto.TransactionFilter,
+	enc exporter.Encoder,
+) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return err
+	}
+
+	err = repository.StreamTransactionsByWorkspace(s.DB, workspaceID, filter, enc.GroupByAccount(), enc.Write)
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
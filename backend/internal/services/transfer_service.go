@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TransferService struct {
+	DB    *gorm.DB
+	cache *cache.Cache
+}
+
+// Construtor
+func NewTransferService(db *gorm.DB, cache *cache.Cache) *TransferService {
+	return &TransferService{DB: db, cache: cache}
+}
+
+// Transfere valor entre duas contas do workspace
+func (s *TransferService) CreateTransfer(userID, workspaceID uint, input dto.TransferInput) (*models.Transfer, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedDate, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return nil, errors.New("data inválida")
+	}
+
+	transfer := &models.Transfer{
+		WorkspaceID:   workspaceID,
+		UserID:        &userID,
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		Amount:        input.Amount,
+		Description:   input.Description,
+		Date:          parsedDate,
+	}
+
+	if err := repository.CreateTransfer(s.DB, transfer); err != nil {
+		return nil, err
+	}
+
+	// Invalida cache de contas do workspace
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
+
+	return transfer, nil
+}
+
+// Lista transferências com paginação
+func (s *TransferService) ListTransfers(userID, workspaceID uint, page, limit int) ([]models.Transfer, int, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return repository.FindTransfersByWorkspace(s.DB, workspaceID, page, limit)
+}
+
+// Deleta transferência
+func (s *TransferService) DeleteTransfer(userID, workspaceID, transferID uint) error {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	if err := repository.DeleteTransferByWorkspace(s.DB, workspaceID, transferID); err != nil {
+		return err
+	}
+
+	// Invalida cache de contas do workspace
+	s.cache.InvalidateWorkspaceAccounts(workspaceID)
+
+	return nil
+}
+
+// Calcula total de páginas
+func (s *TransferService) TotalPages(total, limit int) int {
+	return int(math.Ceil(float64(total) / float64(limit)))
+}
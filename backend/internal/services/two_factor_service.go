@@ -0,0 +1,205 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/auth"
+	"github.com/daviolvr/Fintrack/internal/cache"
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Nome exibido no app autenticador
+const totpIssuer = "Fintrack"
+
+// Quantidade de códigos de recuperação gerados
+const recoveryCodeCount = 10
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("autenticação em dois fatores já está ativa")
+	ErrTwoFactorNotEnabled     = errors.New("autenticação em dois fatores não está ativa")
+	ErrTwoFactorNotStarted     = errors.New("configuração do 2FA não iniciada")
+	ErrInvalidTwoFactorCode    = errors.New("código inválido")
+	ErrIncorrectPassword       = errors.New("senha incorreta")
+)
+
+type TwoFactorService struct {
+	DB    *gorm.DB
+	cache *cache.Cache
+}
+
+// Construtor
+func NewTwoFactorService(db *gorm.DB, cache *cache.Cache) *TwoFactorService {
+	return &TwoFactorService{DB: db, cache: cache}
+}
+
+// Gera um novo segredo pendente e retorna a URI para o app autenticador
+func (s *TwoFactorService) Setup(userID uint) (*dto.TwoFactorSetupResponse, error) {
+	user, err := repository.FindUserByID(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.SetPendingTOTPSecret(s.DB, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(secret, totpIssuer, user.Email),
+	}, nil
+}
+
+// Confirma a configuração com o primeiro código, ativa o 2FA e retorna os códigos de recuperação
+func (s *TwoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	user, err := repository.FindUserByID(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == nil {
+		return nil, ErrTwoFactorNotStarted
+	}
+
+	counter, ok := auth.ValidateTOTP(*user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.EnableTOTP(s.DB, userID, counter, hashes); err != nil {
+		return nil, err
+	}
+	s.cache.InvalidateUserData(userID)
+
+	return codes, nil
+}
+
+// Desativa o 2FA (exige senha e um código válido)
+func (s *TwoFactorService) Disable(userID uint, password, code string) error {
+	user, err := repository.FindUserByID(s.DB, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if !utils.CheckPasswordHash(password, user.Password) {
+		return ErrIncorrectPassword
+	}
+
+	ok, err := verifySecondFactor(s.DB, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	if err := repository.DisableTOTP(s.DB, userID); err != nil {
+		return err
+	}
+
+	return s.cache.InvalidateUserData(userID)
+}
+
+// Gera novos códigos de recuperação, invalidando os anteriores
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	user, err := repository.FindUserByID(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	ok, err := verifySecondFactor(s.DB, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.ReplaceRecoveryCodes(s.DB, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Valida um código TOTP de 6 dígitos ou, caso contrário, um código de recuperação.
+// Ambos são de uso único.
+func verifySecondFactor(db *gorm.DB, user *models.User, code string) (bool, error) {
+	if user.TOTPSecret == nil {
+		return false, nil
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == 6 {
+		counter, ok := auth.ValidateTOTP(*user.TOTPSecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return repository.UseTOTPCounter(db, user.ID, counter)
+	}
+
+	return repository.UseRecoveryCode(db, user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+// Gera os códigos de recuperação (formato "xxxxx-xxxxx") e seus hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz123456789" // 32 caracteres: sem viés no módulo
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = alphabet[int(b[j])%len(alphabet)]
+		}
+
+		code := string(b[:5]) + "-" + string(b[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	return codes, hashes, nil
+}
+
+// Ignora hífens, espaços e maiúsculas ao comparar códigos de recuperação
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
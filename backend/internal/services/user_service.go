@@ -8,11 +8,16 @@ import (
 	"github.com/daviolvr/Fintrack/internal/cache"
 	"github.com/daviolvr/Fintrack/internal/dto"
 	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/money"
 	"github.com/daviolvr/Fintrack/internal/repository"
 	"github.com/daviolvr/Fintrack/internal/utils"
 	"gorm.io/gorm"
 )
 
+var ErrOwnsSharedWorkspace = errors.New(
+	"você é dono de workspaces com outros membros: remova os membros ou exclua esses workspaces antes de excluir a conta",
+)
+
 type UserService struct {
 	DB    *gorm.DB
 	cache *cache.Cache
@@ -69,16 +74,14 @@ func (s *UserService) UpdateUser(
 	return s.GetUser(userID)
 }
 
-// Atualiza o saldo
-func (s *UserService) UpdateBalance(userID uint, balance float64) error {
-	user := models.User{
-		ID:      userID,
-		Balance: balance,
-	}
-	if err := repository.UpdateUserBalance(s.DB, &user); err != nil {
-		return err
+// Retorna o saldo total somando todas as contas do workspace pessoal do usuário
+func (s *UserService) GetTotalBalance(userID uint) (money.Amount, error) {
+	workspaceID, err := authorizeWorkspace(s.DB, userID, 0, RoleViewer)
+	if err != nil {
+		return 0, err
 	}
-	return s.cache.InvalidateUserData(userID)
+
+	return repository.SumAccountBalancesByWorkspace(s.DB, workspaceID)
 }
 
 // Deleta usuário
@@ -92,10 +95,41 @@ func (s *UserService) DeleteUser(userID uint, password string) error {
 		return errors.New("senha incorreta")
 	}
 
-	if err := repository.DeleteUser(s.DB, userID); err != nil {
+	workspaces, err := repository.FindWorkspacesByUser(s.DB, userID)
+	if err != nil {
 		return err
 	}
 
+	// Os workspaces do usuário são excluídos com ele; nos compartilhados com outros
+	// membros, os dados que ele criou permanecem, sem autor (user_id nulo)
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		shared, err := repository.CountSharedWorkspacesOwnedBy(tx, userID)
+		if err != nil {
+			return err
+		}
+		if shared > 0 {
+			return ErrOwnsSharedWorkspace
+		}
+
+		// As ocorrências eram geradas em nome dele: as regras param até alguém editá-las
+		if err := repository.DeactivateRecurringTransactionsByUser(
+			tx, userID, ErrRecurringAuthorDeleted.Error(), time.Now(),
+		); err != nil {
+			return err
+		}
+
+		return repository.DeleteUser(tx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, workspace := range workspaces {
+		s.cache.InvalidateWorkspaceAccounts(workspace.ID)
+		s.cache.InvalidateWorkspaceCategories(workspace.ID)
+		s.cache.InvalidateWorkspaceTransactions(workspace.ID)
+	}
+
 	return s.cache.InvalidateUserData(userID)
 }
 
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/daviolvr/Fintrack/internal/auth"
+	"github.com/daviolvr/Fintrack/internal/dto"
+	"github.com/daviolvr/Fintrack/internal/mailer"
+	"github.com/daviolvr/Fintrack/internal/models"
+	"github.com/daviolvr/Fintrack/internal/repository"
+	"github.com/daviolvr/Fintrack/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Papéis dos membros de um workspace
+const (
+	RoleOwner  = "owner"  // Tudo, inclusive gerenciar membros e excluir o workspace
+	RoleEditor = "editor" // Lê e altera contas, categorias e transações
+	RoleViewer = "viewer" // Apenas leitura
+)
+
+// Validade dos convites enviados por email
+const workspaceInvitationTTL = time.Hour * 24 * 7
+
+var roleRank = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+var (
+	ErrWorkspaceNotFound      = errors.New("workspace não encontrado")
+	ErrWorkspaceForbidden     = errors.New("permissão insuficiente neste workspace")
+	ErrPersonalWorkspace      = errors.New("o workspace pessoal não pode ser excluído")
+	ErrInvalidInvitation      = errors.New("convite inválido ou expirado")
+	ErrInvitationEmail        = errors.New("este convite foi enviado para outro email")
+	ErrAlreadyWorkspaceMember = errors.New("usuário já é membro deste workspace")
+)
+
+type WorkspaceService struct {
+	DB     *gorm.DB
+	mailer mailer.Mailer
+}
+
+// Construtor
+func NewWorkspaceService(db *gorm.DB, mailer mailer.Mailer) *WorkspaceService {
+	return &WorkspaceService{DB: db, mailer: mailer}
+}
+
+// Cria um workspace tendo o usuário como dono
+func (s *WorkspaceService) CreateWorkspace(userID uint, input dto.WorkspaceInput) (*models.Workspace, error) {
+	workspace := &models.Workspace{
+		Name:    input.Name,
+		OwnerID: userID,
+	}
+	if err := repository.CreateWorkspace(s.DB, workspace); err != nil {
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// Lista os workspaces do usuário
+func (s *WorkspaceService) ListWorkspaces(userID uint) ([]repository.WorkspaceWithRole, error) {
+	return repository.FindWorkspacesByUser(s.DB, userID)
+}
+
+// Exclui um workspace compartilhado e todos os seus dados. Apenas o dono.
+func (s *WorkspaceService) DeleteWorkspace(userID, workspaceID uint) error {
+	if _, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleOwner); err != nil {
+		return err
+	}
+
+	workspace, err := repository.FindWorkspaceByID(s.DB, workspaceID)
+	if err != nil {
+		return err
+	}
+	if workspace.Personal {
+		return ErrPersonalWorkspace
+	}
+
+	return repository.DeleteWorkspace(s.DB, workspaceID)
+}
+
+// Lista os membros do workspace
+func (s *WorkspaceService) ListMembers(userID, workspaceID uint) ([]repository.WorkspaceMemberRow, error) {
+	if _, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return repository.FindWorkspaceMembers(s.DB, workspaceID)
+}
+
+// Altera o papel de um membro. Apenas o dono.
+func (s *WorkspaceService) UpdateMemberRole(userID, workspaceID, memberID uint, role string) error {
+	if _, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleOwner); err != nil {
+		return err
+	}
+
+	return repository.UpdateWorkspaceMemberRole(s.DB, workspaceID, memberID, role)
+}
+
+// Remove um membro. O dono remove qualquer membro; os demais só podem sair (remover a si mesmos).
+func (s *WorkspaceService) RemoveMember(userID, workspaceID, memberID uint) error {
+	minRole := RoleOwner
+	if memberID == userID {
+		minRole = RoleViewer
+	}
+	if _, err := authorizeWorkspace(s.DB, userID, workspaceID, minRole); err != nil {
+		return err
+	}
+
+	return repository.RemoveWorkspaceMember(s.DB, workspaceID, memberID)
+}
+
+// Convida um email para o workspace. Apenas o dono.
+func (s *WorkspaceService) Invite(
+	userID, workspaceID uint,
+	input dto.WorkspaceInvitationInput,
+) (*models.WorkspaceInvitation, error) {
+	if _, err := authorizeWorkspace(s.DB, userID, workspaceID, RoleOwner); err != nil {
+		return nil, err
+	}
+
+	workspace, err := repository.FindWorkspaceByID(s.DB, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evita convidar quem já participa
+	if invited, err := repository.FindUserByEmail(s.DB, input.Email); err == nil {
+		if _, err := repository.FindWorkspaceMember(s.DB, workspaceID, invited.ID); err == nil {
+			return nil, ErrAlreadyWorkspaceMember
+		}
+	}
+
+	inviter, err := repository.FindUserByID(s.DB, userID)
+	if err != nil || inviter == nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	token, err := auth.NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &models.WorkspaceInvitation{
+		WorkspaceID: workspaceID,
+		Email:       input.Email,
+		Role:        input.Role,
+		TokenHash:   utils.HashToken(token),
+		InvitedByID: userID,
+		ExpiresAt:   time.Now().Add(workspaceInvitationTTL),
+	}
+	if err := repository.CreateWorkspaceInvitation(s.DB, invitation); err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf(
+		"Olá!\n\n%s %s convidou você para o workspace \"%s\" no Fintrack. "+
+			"Para aceitar, entre na sua conta (ou cadastre-se com este email) e acesse o link abaixo (válido por 7 dias):\n\n"+
+			"%s/invitations/accept?token=%s",
+		inviter.FirstName, inviter.LastName, workspace.Name, frontendURL(), token,
+	)
+	if err := s.mailer.Send(input.Email, "Convite para workspace - Fintrack", body); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// Aceita um convite: o usuário autenticado precisa ter o email convidado.
+// Retorna o workspace e o papel recebido.
+func (s *WorkspaceService) AcceptInvitation(userID uint, token string) (*models.Workspace, string, error) {
+	user, err := repository.FindUserByID(s.DB, userID)
+	if err != nil || user == nil {
+		return nil, "", errors.New("usuário não encontrado")
+	}
+
+	var workspace *models.Workspace
+	var role string
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		invitation, err := repository.AcceptWorkspaceInvitation(tx, utils.HashToken(token), time.Now())
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidInvitation
+			}
+			return err
+		}
+
+		if !strings.EqualFold(invitation.Email, user.Email) {
+			return ErrInvitationEmail
+		}
+
+		if err := repository.AddWorkspaceMember(tx, &models.WorkspaceMember{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      userID,
+			Role:        invitation.Role,
+		}); err != nil {
+			return err
+		}
+		role = invitation.Role
+
+		workspace, err = repository.FindWorkspaceByID(tx, invitation.WorkspaceID)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return workspace, role, nil
+}
+
+// Confere se o usuário tem ao menos o papel exigido no workspace e retorna o ID do workspace.
+// workspaceID 0 significa o workspace pessoal do usuário.
+func authorizeWorkspace(db *gorm.DB, userID, workspaceID uint, minRole string) (uint, error) {
+	if workspaceID == 0 {
+		workspace, err := repository.FindPersonalWorkspace(db, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, ErrWorkspaceNotFound
+			}
+			return 0, err
+		}
+		workspaceID = workspace.ID
+	}
+
+	member, err := repository.FindWorkspaceMember(db, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrWorkspaceNotFound
+		}
+		return 0, err
+	}
+
+	if roleRank[member.Role] < roleRank[minRole] {
+		return 0, ErrWorkspaceForbidden
+	}
+
+	return workspaceID, nil
+}
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/daviolvr/Fintrack/internal/money"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 var (
@@ -35,6 +37,13 @@ func GetUserID(c *gin.Context) (uint, error) {
 	return userID, nil
 }
 
+// Pega o workspace selecionado pelo header X-Workspace-ID (0 = workspace pessoal)
+func GetWorkspaceID(c *gin.Context) uint {
+	workspaceID, _ := c.Get("workspace_id")
+	id, _ := workspaceID.(uint)
+	return id
+}
+
 // Retorna um erro padronizado em JSON
 func RespondError(c *gin.Context, status int, msg string) {
 	c.JSON(status, gin.H{"error": msg})
@@ -54,9 +63,9 @@ func BindJSON(c *gin.Context, obj any) bool {
 	return true
 }
 
-// Checa se é sql.ErrNoRows e responde NotFound
+// Checa se é sql.ErrNoRows (ou gorm.ErrRecordNotFound) e responde NotFound
 func HandleNotFound(c *gin.Context, err error, msg string) bool {
-	if err == sql.ErrNoRows {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, gorm.ErrRecordNotFound) {
 		RespondError(c, http.StatusNotFound, msg)
 		return true
 	}
@@ -113,11 +122,11 @@ func FormatUint(u *uint) string {
 	return fmt.Sprintf("%d", *u)
 }
 
-func FormatFloat(f *float64) string {
-	if f == nil {
+func FormatAmount(a *money.Amount) string {
+	if a == nil {
 		return "nil"
 	}
-	return fmt.Sprintf("%.2f", *f)
+	return a.String()
 }
 
 func FormatString(s *string) string {
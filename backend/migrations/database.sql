@@ -4,27 +4,382 @@ CREATE TABLE IF NOT EXISTS users (
     last_name TEXT NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
-    balance NUMERIC(15,2) NOT NULL DEFAULT 0.00,
     failed_logins INT NOT NULL DEFAULT 0,
     locked_until TIMESTAMP WITH TIME ZONE,
+    totp_secret VARCHAR(64),
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_last_counter BIGINT NOT NULL DEFAULT 0,
+    email_verified_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
 
-CREATE TABLE IF NOT EXISTS categories (
+-- Workspaces: espaços financeiros compartilhados. Todo usuário recebe um workspace pessoal no cadastro.
+CREATE TABLE IF NOT EXISTS workspaces (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    personal BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Um único workspace pessoal por usuário
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspaces_personal ON workspaces (owner_id) WHERE personal;
+
+CREATE TABLE IF NOT EXISTS workspace_members (
     id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
     user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    name VARCHAR(100) NOT NULL
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user ON workspace_members (user_id);
+
+-- Convites por email (hash SHA-256 do token)
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('editor', 'viewer')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('checking', 'savings', 'cash', 'credit_card')),
+    balance NUMERIC(15,2) NOT NULL DEFAULT 0.00,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(10) NOT NULL DEFAULT 'both' CHECK (type IN ('income', 'expense', 'both')),
+    color VARCHAR(7) NOT NULL DEFAULT '',
+    icon VARCHAR(50) NOT NULL DEFAULT '',
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+-- Nomes de categoria são únicos por workspace, sem diferenciar maiúsculas
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_workspace_name ON categories (workspace_id, lower(name));
+
+-- Pessoas com quem as despesas são divididas
+CREATE TABLE IF NOT EXISTS participants (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (workspace_id, user_id)
 );
 
 CREATE TABLE IF NOT EXISTS transactions (
     id SERIAL PRIMARY KEY,
-    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    account_id INTEGER NOT NULL REFERENCES accounts(id),
+    category_id INTEGER NOT NULL REFERENCES categories(id),
     type VARCHAR(10) NOT NULL CHECK (type IN ('income', 'expense')),
     amount NUMERIC(12, 2) NOT NULL CHECK (amount >= 0),
     description TEXT,
     date DATE NOT NULL,
+    recurring_id INTEGER,
+    fitid VARCHAR(255),
+    split_type VARCHAR(10) CHECK (split_type IN ('equal', 'exact', 'percent', 'shares')),
+    payer_id INTEGER REFERENCES participants(id),
+    share_amount NUMERIC(12, 2),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_workspace_date ON transactions (workspace_id, date);
+
+-- Paginação por cursor: ordenação por coluna com o ID como desempate
+CREATE INDEX IF NOT EXISTS idx_transactions_workspace_date_id ON transactions (workspace_id, date, id);
+CREATE INDEX IF NOT EXISTS idx_transactions_workspace_amount_id ON transactions (workspace_id, amount, id);
+CREATE INDEX IF NOT EXISTS idx_transactions_workspace_created_at_id ON transactions (workspace_id, created_at, id);
+
+-- Divisão de uma despesa entre participantes
+CREATE TABLE IF NOT EXISTS transaction_splits (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    participant_id INTEGER NOT NULL REFERENCES participants(id),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount >= 0),
+    UNIQUE (transaction_id, participant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_participant ON transaction_splits (participant_id);
+
+-- Etiquetas livres das transações
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Nomes de etiqueta são únicos por workspace, sem diferenciar maiúsculas
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_workspace_name ON tags (workspace_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (transaction_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag ON transaction_tags (tag_id);
+
+-- Pagamentos entre participantes que quitam dívidas das divisões
+CREATE TABLE IF NOT EXISTS settlements (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    from_participant_id INTEGER NOT NULL REFERENCES participants(id),
+    to_participant_id INTEGER NOT NULL REFERENCES participants(id),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    date DATE NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (from_participant_id <> to_participant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlements_workspace ON settlements (workspace_id, date);
+
+-- FITID do extrato OFX: impede importar a mesma transação duas vezes na mesma conta
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_account_fitid
+    ON transactions (account_id, fitid) WHERE fitid IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS transfers (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    from_account_id INTEGER NOT NULL REFERENCES accounts(id),
+    to_account_id INTEGER NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    description TEXT,
+    date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id),
+    type VARCHAR(10) NOT NULL CHECK (type IN ('income', 'expense')),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    description TEXT,
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    day_of_month INT CHECK (day_of_month BETWEEN 1 AND 31),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    max_occurrences INT CHECK (max_occurrences > 0),
+    occurrences INT NOT NULL DEFAULT 0,
+    next_run_date DATE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_error VARCHAR(255),
+    failed_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-);
\ No newline at end of file
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_transactions_next_run
+    ON recurring_transactions (next_run_date) WHERE active;
+
+-- Garante que cada ocorrência de uma regra recorrente seja gerada uma única vez
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_recurring_id_fkey;
+ALTER TABLE transactions ADD CONSTRAINT transactions_recurring_id_fkey
+    FOREIGN KEY (recurring_id) REFERENCES recurring_transactions(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_recurring_occurrence
+    ON transactions (recurring_id, date) WHERE recurring_id IS NOT NULL;
+
+-- Remover uma categoria não apaga mais suas transações: elas precisam ser movidas antes
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_category_id_fkey;
+ALTER TABLE transactions ADD CONSTRAINT transactions_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id);
+
+-- Busca textual nas descrições: português com stemming e sem acentos,
+-- com trigramas para palavras parciais
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent não é IMMUTABLE, o que impede seu uso em índices
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portuguese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION portuguese_unaccent (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION portuguese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_description_fts
+    ON transactions USING GIN (to_tsvector('portuguese_unaccent', coalesce(description, '')));
+CREATE INDEX IF NOT EXISTS idx_transactions_description_trgm
+    ON transactions USING GIN (immutable_unaccent(lower(coalesce(description, ''))) gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS budgets (
+    id SERIAL PRIMARY KEY,
+    workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    category_id INTEGER NOT NULL REFERENCES categories(id),
+    limit_amount NUMERIC(12, 2) NOT NULL CHECK (limit_amount > 0),
+    start_month DATE NOT NULL,
+    end_month DATE,
+    rollover BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (workspace_id, category_id, start_month),
+    CHECK (end_month IS NULL OR end_month >= start_month)
+);
+
+-- Remover uma categoria também não apaga suas recorrências e orçamentos
+ALTER TABLE recurring_transactions DROP CONSTRAINT IF EXISTS recurring_transactions_category_id_fkey;
+ALTER TABLE recurring_transactions ADD CONSTRAINT recurring_transactions_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id);
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_category_id_fkey;
+ALTER TABLE budgets ADD CONSTRAINT budgets_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id);
+
+-- user_id nos dados do workspace indica só quem criou: excluir o usuário mantém os
+-- registros nos workspaces compartilhados, sem autor
+ALTER TABLE accounts ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_user_id_fkey;
+ALTER TABLE accounts ADD CONSTRAINT accounts_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE categories ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_user_id_fkey;
+ALTER TABLE categories ADD CONSTRAINT categories_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE transactions ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_user_id_fkey;
+ALTER TABLE transactions ADD CONSTRAINT transactions_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE tags ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_user_id_fkey;
+ALTER TABLE tags ADD CONSTRAINT tags_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE settlements ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE settlements DROP CONSTRAINT IF EXISTS settlements_user_id_fkey;
+ALTER TABLE settlements ADD CONSTRAINT settlements_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE transfers ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_user_id_fkey;
+ALTER TABLE transfers ADD CONSTRAINT transfers_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE recurring_transactions ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE recurring_transactions DROP CONSTRAINT IF EXISTS recurring_transactions_user_id_fkey;
+ALTER TABLE recurring_transactions ADD CONSTRAINT recurring_transactions_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE budgets ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_user_id_fkey;
+ALTER TABLE budgets ADD CONSTRAINT budgets_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+-- Refresh tokens emitidos. Tokens rotacionados de um mesmo login compartilham a família.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    jti VARCHAR(64) NOT NULL UNIQUE,
+    family_id VARCHAR(64) NOT NULL,
+    replaced_by VARCHAR(64),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens (user_id) WHERE revoked_at IS NULL;
+
+-- Códigos de recuperação do 2FA (hash SHA-256, uso único)
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user ON recovery_codes (user_id) WHERE used_at IS NULL;
+
+-- Tokens de uso único enviados por email (hash SHA-256)
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL CHECK (purpose IN ('password_reset', 'email_verification')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user ON user_tokens (user_id, purpose) WHERE used_at IS NULL;
+
+-- Chaves de API pessoais (hash SHA-256 da chave)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('read', 'write')),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
+
+-- Sessões de login (uma por dispositivo). family_id liga a sessão aos seus refresh tokens.
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id VARCHAR(64) NOT NULL UNIQUE,
+    user_agent VARCHAR(255),
+    ip VARCHAR(45),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions (user_id) WHERE revoked_at IS NULL;
+
+-- Histórico de tentativas de login. user_id é nulo quando o email não pertence a nenhum usuário.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255),
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(50),
+    ip VARCHAR(45),
+    user_agent VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user ON login_attempts (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_failed ON login_attempts (ip, created_at) WHERE success = FALSE;